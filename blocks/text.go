@@ -1,6 +1,8 @@
 package blocks
 
 import (
+	"unicode/utf8"
+
 	"github.com/hajimehoshi/ebiten/v2/text/v2"
 	"github.com/kettek/rebui"
 )
@@ -10,6 +12,7 @@ type Text struct {
 	Width  float64
 	Height float64
 	Text   string
+	Offset int // Offset is the byte offset of Text within the string the blocks were created from.
 }
 
 // FromText creates a slice of Blocks from a provided string.
@@ -18,12 +21,12 @@ func FromText(txt string, cfg Config) []Block {
 
 	var currentBlock Text
 	var currentWidth float64
-	for _, r := range txt {
+	for i, r := range txt {
 		if r == '\n' {
 			currentBlock.Width, currentBlock.Height = text.Measure(currentBlock.Text, cfg.Face, 0)
 			blocks = append(blocks, currentBlock)
 			blocks = append(blocks, Break{})
-			currentBlock = Text{}
+			currentBlock = Text{Offset: i + 1}
 			currentWidth = 0
 			continue
 		}
@@ -39,11 +42,12 @@ func FromText(txt string, cfg Config) []Block {
 				for i := len(currentBlock.Text) - 1; i >= 0; i-- {
 					if currentBlock.Text[i] == ' ' {
 						txt := currentBlock.Text
+						offset := currentBlock.Offset
 						currentBlock.Width, currentBlock.Height = text.Measure(currentBlock.Text[:i], cfg.Face, 0)
 						currentBlock.Text = txt[:i]
 						blocks = append(blocks, currentBlock)
 						blocks = append(blocks, Break{})
-						currentBlock = Text{Text: txt[i+1:]}
+						currentBlock = Text{Text: txt[i+1:], Offset: offset + i + 1}
 						didit = true
 						break
 					}
@@ -66,15 +70,20 @@ func FromText(txt string, cfg Config) []Block {
 
 func genToPreviousRune(cfg Config, blocks []Block, currentBlock Text) ([]Block, Text) {
 	for i := len(currentBlock.Text) - 1; i >= 0; i-- {
+		// Never split a rune.
+		if !utf8.RuneStart(currentBlock.Text[i]) {
+			continue
+		}
 		width, height := text.Measure(currentBlock.Text[:i], cfg.Face, 0)
 		if width < cfg.Width {
 			txt := currentBlock.Text
+			offset := currentBlock.Offset
 			currentBlock.Width = width
 			currentBlock.Height = height
 			currentBlock.Text = txt[:i]
 			blocks = append(blocks, currentBlock)
 			blocks = append(blocks, Break{})
-			currentBlock = Text{Text: txt[i:]}
+			currentBlock = Text{Text: txt[i:], Offset: offset + i}
 			break
 		}
 	}
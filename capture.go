@@ -0,0 +1,68 @@
+package rebui
+
+import (
+	"github.com/kettek/rebui/events"
+	"github.com/kettek/rebui/widgets/receivers"
+)
+
+// CapturePointer captures the pointer for the node, so that the pointer's moves and releases are dispatched to the node regardless of what is under the pointer. The capture is released when the pointer is released. If another node held the capture, it is sent a LostCapture event before the node is sent a GotCapture event.
+func (l *Layout) CapturePointer(n *Node, pointerID events.PointerID) {
+	if n == nil {
+		l.ReleasePointerCapture(pointerID)
+		return
+	}
+	if l.captures[pointerID] == n {
+		return
+	}
+	l.ReleasePointerCapture(pointerID)
+	if l.captures == nil {
+		l.captures = make(map[events.PointerID]*Node)
+	}
+	l.captures[pointerID] = n
+
+	gotCaptureEvent := &events.GotCapture{
+		Timestamp: events.Timestamp{Timestamp: l.now()},
+		PointerID: pointerID,
+	}
+	retarget(gotCaptureEvent, n, n, events.PhaseTarget)
+	if n.OnGotCapture != nil {
+		n.OnGotCapture(gotCaptureEvent)
+	}
+	if hcapture, ok := n.Widget.(receivers.GotCapture); ok {
+		hcapture.HandleGotCapture(gotCaptureEvent)
+	}
+}
+
+// ReleasePointerCapture releases the capture of the pointer, if any, sending a LostCapture event to the node that held it.
+func (l *Layout) ReleasePointerCapture(pointerID events.PointerID) {
+	n, ok := l.captures[pointerID]
+	if !ok {
+		return
+	}
+	delete(l.captures, pointerID)
+
+	lostCaptureEvent := &events.LostCapture{
+		Timestamp: events.Timestamp{Timestamp: l.now()},
+		PointerID: pointerID,
+	}
+	retarget(lostCaptureEvent, n, n, events.PhaseTarget)
+	if n.OnLostCapture != nil {
+		n.OnLostCapture(lostCaptureEvent)
+	}
+	if hcapture, ok := n.Widget.(receivers.LostCapture); ok {
+		hcapture.HandleLostCapture(lostCaptureEvent)
+	}
+}
+
+// PointerCaptor returns the node that has captured the pointer, or nil if it is not captured.
+func (l *Layout) PointerCaptor(pointerID events.PointerID) *Node {
+	return l.captures[pointerID]
+}
+
+// pointerTarget returns the node that the pointer's events are dispatched to, which is its captor if it is captured or otherwise the topmost node under it.
+func (l *Layout) pointerTarget(p events.Pointer) *Node {
+	if n, ok := l.captures[p.ID()]; ok {
+		return n
+	}
+	return l.hitTarget(p.X, p.Y)
+}
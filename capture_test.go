@@ -0,0 +1,175 @@
+package rebui_test
+
+import (
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/kettek/rebui"
+	"github.com/kettek/rebui/rebuitest"
+	_ "github.com/kettek/rebui/widgets"
+)
+
+func TestCapturedRelease(t *testing.T) {
+	h := rebuitest.New(t, 320, 240,
+		rebui.Node{
+			Type:   "Area",
+			ID:     "a",
+			Width:  "100",
+			Height: "100",
+			Children: rebui.Nodes{
+				{
+					Type:   "Area",
+					ID:     "child",
+					X:      "60",
+					Y:      "60",
+					Width:  "30",
+					Height: "30",
+				},
+			},
+		},
+		rebui.Node{
+			Type:   "Area",
+			ID:     "b",
+			X:      "after a",
+			Width:  "100",
+			Height: "100",
+		},
+	)
+
+	released := map[string]int{}
+	pressed := map[string]int{}
+	for _, id := range []string{"a", "child", "b"} {
+		n := h.Node(id)
+		n.OnPointerRelease = func(evt rebui.EventPointerRelease) {
+			if evt.Target == evt.Widget {
+				released[id]++
+			}
+		}
+		n.OnPointerPressed = func(evt rebui.EventPointerPressed) {
+			if evt.Target == evt.Widget {
+				pressed[id]++
+			}
+		}
+	}
+	h.Node("a").OnPointerPress = func(evt rebui.EventPointerPress) {
+		evt.CapturePointer()
+	}
+
+	// Released outside of the captor, the release goes to the captor but it is not pressed.
+	h.Press("a", ebiten.MouseButtonLeft)
+	h.Hover("b")
+	h.Release(ebiten.MouseButtonLeft)
+	if released["a"] != 1 || released["b"] != 0 {
+		t.Errorf("expected the release to go to a, got %v", released)
+	}
+	if len(pressed) != 0 {
+		t.Errorf("expected no presses when released outside of a, got %v", pressed)
+	}
+
+	// Released upon a descendant of the captor, the captor is pressed.
+	clear(released)
+	h.Press("a", ebiten.MouseButtonLeft)
+	h.Hover("child")
+	h.Release(ebiten.MouseButtonLeft)
+	if released["a"] != 1 || released["child"] != 0 {
+		t.Errorf("expected the release to go to a, got %v", released)
+	}
+	if pressed["a"] != 1 || len(pressed) != 1 {
+		t.Errorf("expected a to be pressed when released upon its child, got %v", pressed)
+	}
+}
+
+func TestCaptureTouchAndButton(t *testing.T) {
+	h := rebuitest.New(t, 320, 240,
+		rebui.Node{
+			Type:   "Area",
+			ID:     "a",
+			Width:  "100",
+			Height: "100",
+		},
+		rebui.Node{
+			Type:   "Area",
+			ID:     "b",
+			X:      "after a",
+			Width:  "100",
+			Height: "100",
+		},
+	)
+
+	a, b := h.Node("a"), h.Node("b")
+	var lost, tapsA, tapsB int
+	a.OnPointerPress = func(evt rebui.EventPointerPress) {
+		if evt.TouchID > 0 {
+			tapsA++
+		}
+		evt.CapturePointer()
+	}
+	a.OnLostCapture = func(evt rebui.EventLostCapture) {
+		lost++
+	}
+	b.OnPointerPress = func(evt rebui.EventPointerPress) {
+		tapsB++
+	}
+
+	// A touch may have the same number as a mouse button, but they are separate pointers.
+	button := rebui.PointerID{ID: int(ebiten.MouseButtonRight)}
+	h.Press("a", ebiten.MouseButtonRight)
+	if h.Layout.PointerCaptor(button) != a {
+		t.Fatal("expected a to capture the right mouse button")
+	}
+	h.Tap("b", ebiten.TouchID(button.ID))
+	if tapsA != 0 || tapsB != 1 {
+		t.Errorf("expected the touch to press b rather than a, got %d and %d", tapsA, tapsB)
+	}
+	if lost != 0 || h.Layout.PointerCaptor(button) != a {
+		t.Errorf("expected a to keep its capture after the touch ended, lost it %d times", lost)
+	}
+
+	h.Release(ebiten.MouseButtonRight)
+	if lost != 1 || h.Layout.PointerCaptor(button) != nil {
+		t.Errorf("expected a to lose its capture once the button was released, lost it %d times", lost)
+	}
+}
+
+func TestRemoveCaptor(t *testing.T) {
+	h := rebuitest.New(t, 320, 240,
+		rebui.Node{
+			Type:   "Area",
+			ID:     "a",
+			Width:  "100",
+			Height: "100",
+		},
+		rebui.Node{
+			Type:   "Area",
+			ID:     "b",
+			X:      "after a",
+			Width:  "100",
+			Height: "100",
+		},
+	)
+
+	a := h.Node("a")
+	var lost, released, taps int
+	a.OnPointerPress = func(evt rebui.EventPointerPress) {
+		evt.CapturePointer()
+	}
+	a.OnLostCapture = func(evt rebui.EventLostCapture) {
+		lost++
+	}
+	a.OnPointerRelease = func(evt rebui.EventPointerRelease) {
+		released++
+	}
+	a.OnPointerTap = func(evt rebui.EventPointerTap) {
+		taps++
+	}
+
+	h.Press("a", ebiten.MouseButtonLeft)
+	h.Layout.RemoveNode(a)
+	if lost != 1 || h.Layout.PointerCaptor(rebui.PointerID{ID: int(ebiten.MouseButtonLeft)}) != nil {
+		t.Errorf("expected removing a to release its capture, lost it %d times", lost)
+	}
+	h.Release(ebiten.MouseButtonLeft)
+	if released != 0 || taps != 0 {
+		t.Errorf("expected a removed captor to receive neither the release nor a tap, got %d and %d", released, taps)
+	}
+}
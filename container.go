@@ -0,0 +1,179 @@
+package rebui
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/kettek/rebui/widgets/assigners"
+)
+
+// contentContext returns the area that the node's children are laid out within, which is the node's bounds inset by its padding.
+func (n *Node) contentContext() LayoutContext {
+	return LayoutContext{
+		OuterX:      n.x + n.paddingX,
+		OuterY:      n.y + n.paddingY,
+		OuterWidth:  max(0, n.width-n.paddingX*2),
+		OuterHeight: max(0, n.height-n.paddingY*2),
+	}
+}
+
+// isContainer returns if the node's widget arranges its children.
+func (n *Node) isContainer() bool {
+	_, ok := n.Widget.(ContainerWidget)
+	return ok
+}
+
+// arrangeNode has the node's ContainerWidget position its visible children, then applies the resulting positions and sizes. This must be called after the node and its children have been sized and the node has been positioned.
+func (l *Layout) arrangeNode(n *Node) {
+	cw, ok := n.Widget.(ContainerWidget)
+	if !ok {
+		return
+	}
+	ctx := n.contentContext()
+
+	// Cell containers are already prepared when their children are sized.
+	if _, ok := l.cells[n]; !ok {
+		l.prepareContainer(n, ctx)
+	}
+
+	var arrangements []*Arrangement
+	for _, c := range n.Children {
+		if c.Hidden {
+			continue
+		}
+		arrangements = append(arrangements, &Arrangement{
+			Node:   c,
+			X:      ctx.OuterX,
+			Y:      ctx.OuterY,
+			Width:  c.width,
+			Height: c.height,
+		})
+	}
+
+	cw.Arrange(ctx, arrangements)
+
+	for _, a := range arrangements {
+		c := a.Node
+		if a.Width != c.width || a.Height != c.height {
+			l.assignNodeSize(c, a.Width, a.Height)
+		}
+		c.x, c.y = a.X, a.Y
+		c.isRelativeX, c.isRelativeY = false, false
+		if xs, ok := c.Widget.(assigners.X); ok {
+			xs.AssignX(c.x)
+		}
+		if ys, ok := c.Widget.(assigners.Y); ok {
+			ys.AssignY(c.y)
+		}
+	}
+}
+
+// prepareContainer assigns the gap and tracks of the container, which are relative to its content area.
+func (l *Layout) prepareContainer(n *Node, ctx LayoutContext) {
+	if ga, ok := n.Widget.(assigners.Gap); ok {
+		gapX, _ := l.nodePosition(n, "Gap", n.Gap, ctx.OuterWidth, 0, false)
+		gapY, _ := l.nodePosition(n, "Gap", n.Gap, ctx.OuterHeight, 0, true)
+		ga.AssignGap(gapX, gapY)
+	}
+	if ta, ok := n.Widget.(assigners.Tracks); ok {
+		columns := l.nodeTracks(n, "Columns", n.Columns, ctx.OuterWidth, false)
+		rows := l.nodeTracks(n, "Rows", n.Rows, ctx.OuterHeight, true)
+		ta.AssignTracks(columns, rows)
+	}
+}
+
+// cellContext returns the context that a child of a CellContainerWidget is sized within. The cells of a container are determined once per layout, when the first of its children is sized.
+func (l *Layout) cellContext(p *Node, n *Node) (LayoutContext, bool) {
+	cw, ok := p.Widget.(CellContainerWidget)
+	if !ok || n.Hidden {
+		return LayoutContext{}, false
+	}
+
+	var children []*Node
+	for _, c := range p.Children {
+		if !c.Hidden {
+			children = append(children, c)
+		}
+	}
+
+	cells, ok := l.cells[p]
+	if !ok {
+		ctx := p.contentContext()
+		l.prepareContainer(p, ctx)
+		cells = cw.Cells(ctx, children)
+		if l.cells == nil {
+			l.cells = make(map[*Node][]LayoutContext)
+		}
+		l.cells[p] = cells
+	}
+
+	for i, c := range children {
+		if c == n && i < len(cells) {
+			return cells[i], true
+		}
+	}
+	return LayoutContext{}, false
+}
+
+// splitTracks splits a track definition on whitespace that is not within parentheses, so that expressions such as "calc(50% - 8)" remain whole.
+func splitTracks(s string) (tracks []string) {
+	depth, start := 0, -1
+	for i, r := range s {
+		switch {
+		case r == '(':
+			depth++
+		case r == ')':
+			depth--
+		case (r == ' ' || r == '\t') && depth <= 0:
+			if start >= 0 {
+				tracks = append(tracks, s[start:i])
+				start = -1
+			}
+			continue
+		}
+		if start < 0 {
+			start = i
+		}
+	}
+	if start >= 0 {
+		tracks = append(tracks, s[start:])
+	}
+	return
+}
+
+// parseFraction parses a fractional track such as "2fr".
+func parseFraction(s string) (float64, bool) {
+	v, ok := strings.CutSuffix(s, "fr")
+	if !ok {
+		return 0, false
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	return f, err == nil
+}
+
+// nodeTracks evaluates a track definition of the given node. Each track is either a fraction, such as "1fr", or a position expression relative to outer.
+func (l *Layout) nodeTracks(n *Node, field, s string, outer float64, vertical bool) []Track {
+	var tracks []Track
+	for _, t := range splitTracks(s) {
+		if f, ok := parseFraction(t); ok {
+			tracks = append(tracks, Track{Fraction: f})
+			continue
+		}
+		v, _ := l.nodePosition(n, field, t, outer, 0, vertical)
+		tracks = append(tracks, Track{Size: v})
+	}
+	return tracks
+}
+
+// validateTracks checks each track of the given track definition.
+func (l *Layout) validateTracks(s string, vertical bool) error {
+	for _, t := range splitTracks(s) {
+		if _, ok := parseFraction(t); ok {
+			continue
+		}
+		if err := l.validatePosition(t, vertical); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package rebui
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/kettek/rebui/events"
+	"github.com/kettek/rebui/widgets/getters"
+)
+
+// cursorShapes maps our cursors to Ebitengine's cursor shapes.
+var cursorShapes = map[Cursor]ebiten.CursorShapeType{
+	CursorDefault:    ebiten.CursorShapeDefault,
+	CursorText:       ebiten.CursorShapeText,
+	CursorPointer:    ebiten.CursorShapePointer,
+	CursorCrosshair:  ebiten.CursorShapeCrosshair,
+	CursorEWResize:   ebiten.CursorShapeEWResize,
+	CursorNSResize:   ebiten.CursorShapeNSResize,
+	CursorNESWResize: ebiten.CursorShapeNESWResize,
+	CursorNWSEResize: ebiten.CursorShapeNWSEResize,
+	CursorMove:       ebiten.CursorShapeMove,
+	CursorNotAllowed: ebiten.CursorShapeNotAllowed,
+}
+
+// cursor returns the cursor requested by the node's widget or, if there is none, the node's Cursor.
+func (n *Node) cursor() Cursor {
+	if cg, ok := n.Widget.(getters.Cursor); ok {
+		if c := cg.GetCursor(); c != "" {
+			return c
+		}
+	}
+	return n.Cursor
+}
+
+// cursorNode returns the node that determines the cursor, which is the node that has captured a held mouse button, if any, or otherwise the topmost hovered node.
+func (l *Layout) cursorNode() *Node {
+	for _, mb := range l.pressedMouseButtons {
+		if n, ok := l.captures[events.PointerID{ID: int(mb.id)}]; ok {
+			return n
+		}
+	}
+	if len(l.currentState.hoveredNodes) == 0 {
+		return nil
+	}
+	// Hovered nodes are added from parent to child, so the last is the topmost.
+	n := l.currentState.hoveredNodes[len(l.currentState.hoveredNodes)-1]
+	if !n.isVisible() {
+		return nil
+	}
+	return n
+}
+
+// updateCursor applies the cursor of the cursor node or its nearest parent that has one, or the default cursor if there is none.
+func (l *Layout) updateCursor() {
+	c := CursorDefault
+	for n := l.cursorNode(); n != nil; n = n.Parent {
+		if nc := n.cursor(); nc != "" {
+			c = nc
+			break
+		}
+	}
+	shape, ok := cursorShapes[c]
+	if !ok {
+		c, shape = CursorDefault, ebiten.CursorShapeDefault
+	}
+	// Leave the cursor alone until a node first asks for one, so as not to override a cursor set elsewhere.
+	if c == l.cursor || (l.cursor == "" && c == CursorDefault) {
+		return
+	}
+	l.cursor = c
+	ebiten.SetCursorShape(shape)
+}
@@ -0,0 +1,189 @@
+package rebui
+
+import (
+	"github.com/kettek/rebui/events"
+	"github.com/kettek/rebui/widgets/receivers"
+)
+
+// targetedEvent is an event that can be dispatched through the node tree. This applies to all events.
+type targetedEvent interface {
+	SetTarget(target, current events.Widget, phase events.Phase)
+}
+
+// propagationStopped returns if the event's propagation has been stopped.
+func propagationStopped(e Event) bool {
+	ec, ok := e.(EventCancelable)
+	return ok && ec.Canceled()
+}
+
+// eventPointer returns the pointer information of the event, or nil if it has none.
+func eventPointer(e Event) *events.Pointer {
+	switch evt := e.(type) {
+	case *events.PointerMove:
+		return &evt.Pointer
+	case *events.PointerIn:
+		return &evt.Pointer
+	case *events.PointerOut:
+		return &evt.Pointer
+	case *events.PointerPress:
+		return &evt.Pointer
+	case *events.PointerRelease:
+		return &evt.Pointer
+	case *events.PointerPressed:
+		return &evt.Pointer
+	case *events.PointerWheel:
+		return &evt.Pointer
+	case *events.PointerTap:
+		return &evt.Pointer
+	case *events.PointerDoubleClick:
+		return &evt.Pointer
+	case *events.PointerLongPress:
+		return &evt.Pointer
+	case *events.Pinch:
+		return &evt.Pointer
+	case *events.Rotate:
+		return &evt.Pointer
+	case *events.Pan:
+		return &evt.Pointer
+	case *events.DragStart:
+		return &evt.Pointer
+	case *events.DragEnter:
+		return &evt.Pointer
+	case *events.DragOver:
+		return &evt.Pointer
+	case *events.DragLeave:
+		return &evt.Pointer
+	case *events.Drop:
+		return &evt.Pointer
+	case *events.DragEnd:
+		return &evt.Pointer
+	}
+	return nil
+}
+
+// retarget updates the event for delivery to the given node, including the pointer's position relative to it.
+func retarget(e Event, target, current *Node, phase events.Phase) {
+	if te, ok := e.(targetedEvent); ok {
+		var tw events.Widget
+		if target != nil {
+			tw = target.Widget
+		}
+		te.SetTarget(tw, current.Widget, phase)
+	}
+	if p := eventPointer(e); p != nil {
+		p.RelativeX, p.RelativeY = current.relativePointer(p.X, p.Y)
+	}
+}
+
+// dispatchEvent sends the event through the node tree. During the capture phase it is sent from the root node down to the target's parent through their OnCapture hooks and Capture receivers. It is then delivered to the target and, during the bubble phase, to each of the target's parents in turn. Dispatch ends as soon as the event's propagation is stopped.
+func (l *Layout) dispatchEvent(target *Node, e Event, deliver func(n *Node)) {
+	var path []*Node
+	for n := target; n != nil; n = n.Parent {
+		path = append(path, n)
+	}
+
+	for i := len(path) - 1; i > 0; i-- {
+		n := path[i]
+		retarget(e, target, n, events.PhaseCapture)
+		if n.OnCapture != nil {
+			n.OnCapture(e)
+		}
+		if propagationStopped(e) {
+			return
+		}
+		if hcapture, ok := n.Widget.(receivers.Capture); ok {
+			hcapture.HandleCapture(e)
+		}
+		if propagationStopped(e) {
+			return
+		}
+	}
+
+	for i, n := range path {
+		phase := events.PhaseBubble
+		if i == 0 {
+			phase = events.PhaseTarget
+		}
+		retarget(e, target, n, phase)
+		deliver(n)
+		if propagationStopped(e) {
+			return
+		}
+	}
+}
+
+// hitTarget returns the topmost node hit by the given screen coordinate, or nil if there is none. Nodes drawn later are above those drawn earlier.
+func (l *Layout) hitTarget(x, y float64) *Node {
+	return l.hitTargetExcept(x, y, nil)
+}
+
+// hitTargetExcept is the same as hitTarget, except that the excluded node and its descendants are never hit.
+func (l *Layout) hitTargetExcept(x, y float64, excluded *Node) (target *Node) {
+	l.Nodes.ForEach(func(n *Node) bool {
+		if excluded != nil && excluded.isAncestorOf(n) {
+			return false
+		}
+		if hit, ok := n.Widget.(HitChecker); ok {
+			if _, _, ok := l.hitNode(n, hit, x, y); ok {
+				target = n
+			}
+		}
+		return false
+	})
+	return
+}
+
+// isAncestorOf returns if the node is the other node or one of its parents.
+func (n *Node) isAncestorOf(other *Node) bool {
+	for ; other != nil; other = other.Parent {
+		if other == n {
+			return true
+		}
+	}
+	return false
+}
+
+// updateHover sends PointerOut events to hovered nodes that are no longer the target or one of its parents, then PointerIn events to the target and its parents that were not already hovered. These events do not propagate.
+func (l *Layout) updateHover(target *Node, evt *events.PointerMove) {
+	for i := len(l.currentState.hoveredNodes) - 1; i >= 0; i-- {
+		n := l.currentState.hoveredNodes[i]
+		if n.isAncestorOf(target) {
+			continue
+		}
+		l.currentState.removeHovered(n)
+		pointerOutEvent := &events.PointerOut{
+			Timestamp: evt.Timestamp,
+			Pointer:   evt.Pointer,
+		}
+		retarget(pointerOutEvent, n, n, events.PhaseTarget)
+		if n.OnPointerOut != nil {
+			n.OnPointerOut(pointerOutEvent)
+		}
+		if hout, ok := n.Widget.(receivers.PointerOut); ok {
+			hout.HandlePointerOut(pointerOutEvent)
+		}
+	}
+
+	var entered []*Node
+	for n := target; n != nil; n = n.Parent {
+		if !l.currentState.isHovered(n) {
+			entered = append(entered, n)
+		}
+	}
+	// Parents are entered before their children.
+	for i := len(entered) - 1; i >= 0; i-- {
+		n := entered[i]
+		l.currentState.addHovered(n)
+		pointerInEvent := &events.PointerIn{
+			Timestamp: evt.Timestamp,
+			Pointer:   evt.Pointer,
+		}
+		retarget(pointerInEvent, n, n, events.PhaseTarget)
+		if n.OnPointerIn != nil {
+			n.OnPointerIn(pointerInEvent)
+		}
+		if hin, ok := n.Widget.(receivers.PointerIn); ok {
+			hin.HandlePointerIn(pointerInEvent)
+		}
+	}
+}
@@ -0,0 +1,271 @@
+package rebui
+
+import (
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/kettek/rebui/events"
+	"github.com/kettek/rebui/widgets/getters"
+	"github.com/kettek/rebui/widgets/receivers"
+)
+
+// dragSession is a drag in progress.
+type dragSession struct {
+	source         *Node
+	pid            events.PointerID
+	data           events.DragData
+	ghost          *ebiten.Image
+	ownGhost       bool // If the ghost was drawn by the Layout rather than provided by a DragStart handler, and so should be deallocated when the drag ends.
+	ghostX, ghostY float64
+	x, y           float64 // The pointer's latest position.
+	over           *Node   // The node the drag is over.
+	accepted       bool    // If the node the drag is over accepted it.
+}
+
+// Dragging returns the payload of the drag in progress, if any.
+func (l *Layout) Dragging() (data events.DragData, ok bool) {
+	if l.drag == nil {
+		return data, false
+	}
+	return l.drag.data, true
+}
+
+// CancelDrag ends the drag in progress, if any, without dropping it.
+func (l *Layout) CancelDrag() {
+	d := l.drag
+	if d == nil {
+		return
+	}
+	p := events.Pointer{X: d.x, Y: d.y, Modifiers: l.modifiers}
+	ts := events.Timestamp{Timestamp: l.now()}
+	l.dragLeave(d, p, ts)
+	l.endDrag(d, p, ts, false)
+}
+
+// accepts returns if the node's Accepts includes the drag type.
+func (n *Node) accepts(dragType string) bool {
+	return n.Accepts != "" && slices.Contains(strings.Fields(n.Accepts), dragType)
+}
+
+// draggableNode returns the nearest node from n up through its parents that is draggable and not disabled, or nil if there is none.
+func draggableNode(n *Node) *Node {
+	for ; n != nil; n = n.Parent {
+		if !n.Draggable {
+			continue
+		}
+		disabled := n.Disabled
+		if dg, ok := n.Widget.(getters.Disabled); ok {
+			disabled = dg.GetDisabled()
+		}
+		if disabled {
+			return nil
+		}
+		return n
+	}
+	return nil
+}
+
+// startDrag sends a DragStart event to the gesture's draggable node and, unless its default action is prevented, begins a drag session.
+func (l *Layout) startDrag(g *pointerGesture, evt *events.PointerMove) {
+	source := g.draggable
+	g.draggable = nil // Only attempt to drag once per press.
+
+	ghostX, ghostY := source.relativePointer(evt.X, evt.Y)
+	dragStartEvent := &events.DragStart{
+		Timestamp: evt.Timestamp,
+		Pointer:   evt.Pointer,
+		DragData:  events.DragData{Type: source.DragType, Payload: source},
+		GhostX:    ghostX,
+		GhostY:    ghostY,
+	}
+	l.dispatchEvent(source, dragStartEvent, func(n *Node) {
+		if n.OnDragStart != nil {
+			n.OnDragStart(dragStartEvent)
+		}
+		if dragStartEvent.Canceled() {
+			return
+		}
+		if hdrag, ok := n.Widget.(receivers.DragStart); ok {
+			hdrag.HandleDragStart(dragStartEvent)
+		}
+	})
+	if dragStartEvent.DefaultPrevented() {
+		return
+	}
+
+	l.drag = &dragSession{
+		source: source,
+		pid:    evt.ID(),
+		data:   dragStartEvent.DragData,
+		ghost:  dragStartEvent.Ghost,
+		ghostX: dragStartEvent.GhostX,
+		ghostY: dragStartEvent.GhostY,
+	}
+	l.dragMove(l.drag, evt.Pointer, evt.Timestamp)
+}
+
+// dragMove moves the drag to the pointer's position, sending DragLeave and DragEnter events if the topmost node under it changed, followed by a DragOver event. The source and its children are never the node the drag is over, so that they cannot be dropped onto themselves.
+func (l *Layout) dragMove(d *dragSession, p events.Pointer, ts events.Timestamp) {
+	d.x, d.y = p.X, p.Y
+	target := l.hitTargetExcept(p.X, p.Y, d.source)
+	if target != d.over {
+		l.dragLeave(d, p, ts)
+		d.over = target
+		if target != nil {
+			dragEnterEvent := &events.DragEnter{
+				Timestamp: ts,
+				Pointer:   p,
+				DragData:  d.data,
+			}
+			l.dispatchEvent(target, dragEnterEvent, func(n *Node) {
+				if n.accepts(d.data.Type) {
+					dragEnterEvent.Accept()
+				}
+				if n.OnDragEnter != nil {
+					n.OnDragEnter(dragEnterEvent)
+				}
+				if dragEnterEvent.Canceled() {
+					return
+				}
+				if hdrag, ok := n.Widget.(receivers.DragEnter); ok {
+					hdrag.HandleDragEnter(dragEnterEvent)
+				}
+			})
+			d.accepted = dragEnterEvent.Accepted()
+		}
+	}
+	if target == nil {
+		return
+	}
+
+	dragOverEvent := &events.DragOver{
+		Timestamp: ts,
+		Pointer:   p,
+		DragData:  d.data,
+	}
+	l.dispatchEvent(target, dragOverEvent, func(n *Node) {
+		if n.accepts(d.data.Type) {
+			dragOverEvent.Accept()
+		}
+		if n.OnDragOver != nil {
+			n.OnDragOver(dragOverEvent)
+		}
+		if dragOverEvent.Canceled() {
+			return
+		}
+		if hdrag, ok := n.Widget.(receivers.DragOver); ok {
+			hdrag.HandleDragOver(dragOverEvent)
+		}
+	})
+	d.accepted = dragOverEvent.Accepted()
+}
+
+// dragLeave sends a DragLeave event to the node the drag is over, if any.
+func (l *Layout) dragLeave(d *dragSession, p events.Pointer, ts events.Timestamp) {
+	if d.over == nil {
+		return
+	}
+	dragLeaveEvent := &events.DragLeave{
+		Timestamp: ts,
+		Pointer:   p,
+		DragData:  d.data,
+	}
+	l.dispatchEvent(d.over, dragLeaveEvent, func(n *Node) {
+		if n.OnDragLeave != nil {
+			n.OnDragLeave(dragLeaveEvent)
+		}
+		if dragLeaveEvent.Canceled() {
+			return
+		}
+		if hdrag, ok := n.Widget.(receivers.DragLeave); ok {
+			hdrag.HandleDragLeave(dragLeaveEvent)
+		}
+	})
+	d.over, d.accepted = nil, false
+}
+
+// releaseDrag ends the drag at the pointer's position, sending a Drop event to the node it is over if that node accepted it.
+func (l *Layout) releaseDrag(d *dragSession, p events.Pointer, ts events.Timestamp) {
+	l.dragMove(d, p, ts)
+	dropped := d.over != nil && d.accepted
+	if dropped {
+		dropEvent := &events.Drop{
+			Timestamp: ts,
+			Pointer:   p,
+			DragData:  d.data,
+		}
+		l.dispatchEvent(d.over, dropEvent, func(n *Node) {
+			if n.OnDrop != nil {
+				n.OnDrop(dropEvent)
+			}
+			if dropEvent.Canceled() {
+				return
+			}
+			if hdrop, ok := n.Widget.(receivers.Drop); ok {
+				hdrop.HandleDrop(dropEvent)
+			}
+		})
+	} else {
+		l.dragLeave(d, p, ts)
+	}
+	l.endDrag(d, p, ts, dropped)
+}
+
+// endDrag sends a DragEnd event to the drag's source and clears the drag, deallocating any ghost it drew.
+func (l *Layout) endDrag(d *dragSession, p events.Pointer, ts events.Timestamp, dropped bool) {
+	l.drag = nil
+	if d.ownGhost {
+		d.ghost.Deallocate()
+		d.ghost = nil
+		d.ownGhost = false
+	}
+	dragEndEvent := &events.DragEnd{
+		Timestamp: ts,
+		Pointer:   p,
+		DragData:  d.data,
+		Dropped:   dropped,
+	}
+	l.dispatchEvent(d.source, dragEndEvent, func(n *Node) {
+		if n.OnDragEnd != nil {
+			n.OnDragEnd(dragEndEvent)
+		}
+		if dragEndEvent.Canceled() {
+			return
+		}
+		if hdrag, ok := n.Widget.(receivers.DragEnd); ok {
+			hdrag.HandleDragEnd(dragEndEvent)
+		}
+	})
+}
+
+// drawDrag draws the ghost of the drag in progress under the pointer. If no ghost was provided, one is drawn from the source node and its children.
+func (l *Layout) drawDrag() {
+	d := l.drag
+	if d == nil {
+		return
+	}
+	if d.ghost == nil {
+		n := d.source
+		w, h := int(math.Ceil(n.width)), int(math.Ceil(n.height))
+		if w <= 0 || h <= 0 {
+			return
+		}
+		d.ghost = ebiten.NewImage(w, h)
+		d.ownGhost = true
+		ns := Nodes{n}
+		ns.ForEach(func(c *Node) bool {
+			if c.Widget != nil {
+				op := &ebiten.DrawImageOptions{}
+				op.GeoM.Translate(c.x-n.x, c.y-n.y)
+				c.Widget.Draw(d.ghost, op)
+			}
+			return false
+		})
+	}
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Translate(d.x-d.ghostX, d.y-d.ghostY)
+	op.ColorScale.ScaleAlpha(0.5)
+	l.RenderTarget.DrawImage(d.ghost, op)
+}
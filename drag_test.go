@@ -0,0 +1,135 @@
+package rebui_test
+
+import (
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/kettek/rebui"
+	"github.com/kettek/rebui/rebuitest"
+	_ "github.com/kettek/rebui/widgets"
+)
+
+func newDragHarness(t *testing.T) *rebuitest.Harness {
+	return rebuitest.New(t, 320, 240, rebui.Node{
+		Type:    "Area",
+		ID:      "list",
+		Width:   "200",
+		Height:  "100",
+		Accepts: "item",
+		Children: rebui.Nodes{
+			{
+				Type:      "Area",
+				ID:        "card",
+				Width:     "50",
+				Height:    "50",
+				Draggable: true,
+				DragType:  "item",
+				Accepts:   "item",
+				Children: rebui.Nodes{
+					{
+						Type:   "Area",
+						ID:     "label",
+						Width:  "50",
+						Height: "50",
+					},
+				},
+			},
+		},
+	})
+}
+
+func TestDragOverSource(t *testing.T) {
+	h := newDragHarness(t)
+
+	entered := map[string]int{}
+	dropped := map[string]int{}
+	for _, id := range []string{"list", "card", "label"} {
+		n := h.Node(id)
+		n.OnDragEnter = func(evt rebui.EventDragEnter) {
+			if evt.Target == evt.Widget {
+				entered[id]++
+			}
+		}
+		n.OnDrop = func(evt rebui.EventDrop) {
+			dropped[id]++
+		}
+	}
+	var ended, endDropped bool
+	h.Node("card").OnDragEnd = func(evt rebui.EventDragEnd) {
+		ended, endDropped = true, evt.Dropped
+	}
+
+	// The pointer stays over the card, but the card and its children are skipped for what is beneath them.
+	h.Drag("card", 12, 0, 2)
+	if entered["card"] != 0 || entered["label"] != 0 || entered["list"] != 1 {
+		t.Errorf("expected the drag to enter only the list, got %v", entered)
+	}
+	if dropped["card"] != 0 || dropped["label"] != 0 || dropped["list"] != 1 {
+		t.Errorf("expected the drag to drop only on the list, got %v", dropped)
+	}
+	if !ended || !endDropped {
+		t.Errorf("expected the card to end its drag as dropped, got %v and %v", ended, endDropped)
+	}
+}
+
+func TestClearEventsEndsDrag(t *testing.T) {
+	h := newDragHarness(t)
+
+	var ends, leaves int
+	h.Node("card").OnDragEnd = func(evt rebui.EventDragEnd) {
+		ends++
+		if evt.Dropped {
+			t.Error("expected a cleared drag not to be dropped")
+		}
+	}
+	h.Node("list").OnDragLeave = func(evt rebui.EventDragLeave) {
+		leaves++
+	}
+
+	h.Press("card", ebiten.MouseButtonLeft)
+	x, y := h.Rect("card").Center()
+	h.MoveTo(x+20, y)
+	if _, ok := h.Layout.Dragging(); !ok {
+		t.Fatal("expected the card to be dragged")
+	}
+
+	h.Layout.ClearEvents()
+	if _, ok := h.Layout.Dragging(); ok {
+		t.Error("expected ClearEvents to end the drag")
+	}
+	if ends != 1 || leaves != 1 {
+		t.Errorf("expected the drag to leave the list and end once, got %d leaves and %d ends", leaves, ends)
+	}
+	h.Release(ebiten.MouseButtonLeft)
+	if ends != 1 {
+		t.Errorf("expected no further DragEnd after releasing, got %d", ends)
+	}
+}
+
+func TestRemoveDragged(t *testing.T) {
+	h := newDragHarness(t)
+
+	var ends int
+	h.Node("card").OnDragEnd = func(evt rebui.EventDragEnd) {
+		ends++
+	}
+
+	h.Press("card", ebiten.MouseButtonLeft)
+	x, y := h.Rect("card").Center()
+	h.MoveTo(x+20, y)
+	if _, ok := h.Layout.Dragging(); !ok {
+		t.Fatal("expected the card to be dragged")
+	}
+
+	h.Layout.RemoveNode(h.Node("list"))
+	if _, ok := h.Layout.Dragging(); ok {
+		t.Error("expected removing the dragged node's parent to end the drag")
+	}
+	if ends != 1 {
+		t.Errorf("expected the card to be sent a DragEnd event, got %d", ends)
+	}
+	h.Release(ebiten.MouseButtonLeft)
+	if ends != 1 {
+		t.Errorf("expected no further DragEnd after releasing, got %d", ends)
+	}
+}
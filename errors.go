@@ -0,0 +1,76 @@
+package rebui
+
+import (
+	"errors"
+	"fmt"
+	"log"
+)
+
+// Errors that may occur while validating, generating, or laying out Nodes. These are wrapped within a NodeError, so errors.Is should be used to check for them.
+var (
+	ErrUnknownNodeType   = errors.New("unknown node type")
+	ErrDuplicateID       = errors.New("duplicate ID")
+	ErrUnresolvedID      = errors.New("unresolved ID reference")
+	ErrCircularReference = errors.New("circular reference")
+	ErrBadUnit           = errors.New("bad unit")
+	ErrBadExpression     = errors.New("bad expression")
+	ErrLoaderFailure     = errors.New("loader failure")
+	ErrBadShortcut       = errors.New("bad shortcut")
+	ErrShortcutConflict  = errors.New("shortcut conflict")
+	ErrUnknownCursor     = errors.New("unknown cursor")
+)
+
+// NodeError is an error that occurred while handling a particular field of a Node.
+type NodeError struct {
+	NodeID   string // The ID of the Node, which may be empty.
+	NodeType string // The Type of the Node.
+	Field    string // The Node field that caused the error, such as "X" or "Image".
+	Err      error
+}
+
+func (e *NodeError) Error() string {
+	name := "node"
+	if e.NodeType != "" {
+		name = e.NodeType + " " + name
+	}
+	if e.NodeID != "" {
+		name += fmt.Sprintf(" %q", e.NodeID)
+	}
+	return fmt.Sprintf("%s: %s: %v", name, e.Field, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *NodeError) Unwrap() error {
+	return e.Err
+}
+
+func newNodeError(n *Node, field string, err error) *NodeError {
+	return &NodeError{
+		NodeID:   n.ID,
+		NodeType: n.Type,
+		Field:    field,
+		Err:      err,
+	}
+}
+
+// Errors returns any errors collected from Generate, AddNode, and the most recent Layout. Errors are only collected if CollectErrors is true.
+func (l *Layout) Errors() []error {
+	errs := make([]error, 0, len(l.generateErrors)+len(l.layoutErrors))
+	errs = append(errs, l.generateErrors...)
+	return append(errs, l.layoutErrors...)
+}
+
+// ClearErrors clears any collected errors.
+func (l *Layout) ClearErrors() {
+	l.generateErrors = nil
+	l.layoutErrors = nil
+}
+
+// reportError either collects the error into errs or logs it, depending on CollectErrors.
+func (l *Layout) reportError(errs *[]error, err error) {
+	if l.CollectErrors {
+		*errs = append(*errs, err)
+	} else {
+		log.Println(err)
+	}
+}
@@ -17,6 +17,54 @@ type EventCancelable interface {
 // EventPointerMove is an event that is triggered when a pointer within an element or when an element is pressed and the pointer moves anywhere.
 type EventPointerMove = *events.PointerMove
 
+// EventPointerWheel is an event that is triggered when the mouse wheel is turned over an element.
+type EventPointerWheel = *events.PointerWheel
+
+// EventPointerTap is an event that is triggered when a pointer taps an element, with a count of how many times it has been tapped in quick succession.
+type EventPointerTap = *events.PointerTap
+
+// EventPointerDoubleClick is an event that is triggered when a pointer taps an element twice in quick succession.
+type EventPointerDoubleClick = *events.PointerDoubleClick
+
+// EventPointerLongPress is an event that is triggered when a pointer is held over an element without moving.
+type EventPointerLongPress = *events.PointerLongPress
+
+// EventPinch is an event that is triggered when two touches over an element pinch together or apart.
+type EventPinch = *events.Pinch
+
+// EventRotate is an event that is triggered when two touches over an element turn around each other.
+type EventRotate = *events.Rotate
+
+// EventPan is an event that is triggered when two touches over an element move together.
+type EventPan = *events.Pan
+
+// EventDragStart is an event that is triggered when a drag begins from a draggable element.
+type EventDragStart = *events.DragStart
+
+// EventDragEnter is an event that is triggered when a drag moves over an element.
+type EventDragEnter = *events.DragEnter
+
+// EventDragOver is an event that is triggered when a drag moves within an element.
+type EventDragOver = *events.DragOver
+
+// EventDragLeave is an event that is triggered when a drag leaves an element.
+type EventDragLeave = *events.DragLeave
+
+// EventDrop is an event that is triggered when a drag is dropped on an element that accepted it.
+type EventDrop = *events.Drop
+
+// EventDragEnd is an event that is triggered when the drag of an element ends.
+type EventDragEnd = *events.DragEnd
+
+// EventGotCapture is an event that is triggered when an element captures a pointer.
+type EventGotCapture = *events.GotCapture
+
+// EventLostCapture is an event that is triggered when an element loses its capture of a pointer.
+type EventLostCapture = *events.LostCapture
+
+// PointerID identifies a pointer, such as one that has been captured.
+type PointerID = events.PointerID
+
 // EventPointerIn is an event that is triggered when a pointer enters an element.
 type EventPointerIn = *events.PointerIn
 
@@ -46,3 +94,18 @@ type EventKeyRelease = *events.KeyRelease
 
 // EventKeyInput is used to receive key input events. Only the focused element will receive this event.
 type EventKeyInput = *events.KeyInput
+
+// EventGamepadButtonPress is an event that is triggered when a gamepad button is pressed.
+type EventGamepadButtonPress = *events.GamepadButtonPress
+
+// EventGamepadButtonRelease is an event that is triggered when a gamepad button is released.
+type EventGamepadButtonRelease = *events.GamepadButtonRelease
+
+// EventGamepadAxis is an event that is triggered when a gamepad axis changes.
+type EventGamepadAxis = *events.GamepadAxis
+
+// EventActivate is an event that is triggered when the focused element is activated without a pointer.
+type EventActivate = *events.Activate
+
+// EventCancel is an event that is triggered when the user backs out of the focused element without a pointer.
+type EventCancel = *events.Cancel
@@ -0,0 +1,88 @@
+package events
+
+import "github.com/hajimehoshi/ebiten/v2"
+
+// DragData is the payload carried by a drag.
+type DragData struct {
+	Type    string // The kind of payload, which drop targets use to accept or reject it.
+	Payload any
+}
+
+// Acceptable is a drag event that a drop target can accept.
+type Acceptable struct {
+	accepted bool
+}
+
+// Accept allows the drag to be dropped on the element.
+func (a *Acceptable) Accept() {
+	a.accepted = true
+}
+
+// Reject keeps the drag from being dropped on the element, even if it was accepted by a Node's Accepts.
+func (a *Acceptable) Reject() {
+	a.accepted = false
+}
+
+// Accepted returns true if the drag may be dropped on the element.
+func (a *Acceptable) Accepted() bool {
+	return a.accepted
+}
+
+// DragStart is an event that is triggered when a pointer pressed on a draggable element moves far enough to begin a drag. Handlers may change the DragData and Ghost, or prevent the default action to keep the drag from starting.
+type DragStart struct {
+	Cancelable
+	TargetWidget
+	Timestamp
+	Pointer
+	DragData
+	Ghost          *ebiten.Image // The image drawn under the pointer during the drag. If nil, an image of the element is used.
+	GhostX, GhostY float64       // Where the pointer is within the Ghost.
+}
+
+// DragEnter is an event that is triggered when a drag moves over an element. The drag can only be dropped if it is accepted.
+type DragEnter struct {
+	Cancelable
+	TargetWidget
+	Timestamp
+	Pointer
+	DragData
+	Acceptable
+}
+
+// DragOver is an event that is triggered when a drag moves within an element. The drag can only be dropped if it is accepted.
+type DragOver struct {
+	Cancelable
+	TargetWidget
+	Timestamp
+	Pointer
+	DragData
+	Acceptable
+}
+
+// DragLeave is an event that is triggered when a drag leaves an element.
+type DragLeave struct {
+	Cancelable
+	TargetWidget
+	Timestamp
+	Pointer
+	DragData
+}
+
+// Drop is an event that is triggered when a drag is released over an element that accepted it.
+type Drop struct {
+	Cancelable
+	TargetWidget
+	Timestamp
+	Pointer
+	DragData
+}
+
+// DragEnd is an event that is triggered on the dragged element when its drag ends.
+type DragEnd struct {
+	Cancelable
+	TargetWidget
+	Timestamp
+	Pointer
+	DragData
+	Dropped bool // Whether the drag was dropped on an element that accepted it.
+}
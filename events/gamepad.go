@@ -0,0 +1,53 @@
+package events
+
+import "github.com/hajimehoshi/ebiten/v2"
+
+// Gamepad is the base struct for gamepad events. Buttons and axes use the standard gamepad layout.
+type Gamepad struct {
+	GamepadID ebiten.GamepadID
+}
+
+// GamepadButtonPress is an event that is triggered when a gamepad button is pressed.
+type GamepadButtonPress struct {
+	Cancelable
+	TargetWidget // TargetWidget will be set to the current focused widget
+	Timestamp
+	Gamepad
+	Button ebiten.StandardGamepadButton
+	Repeat int
+}
+
+// GamepadButtonRelease is an event that is triggered when a gamepad button is released.
+type GamepadButtonRelease struct {
+	Cancelable
+	TargetWidget // TargetWidget will be set to the current focused widget
+	Timestamp
+	Duration // How long the button was held.
+	Gamepad
+	Button ebiten.StandardGamepadButton
+}
+
+// GamepadAxis is an event that is triggered when a gamepad axis changes.
+type GamepadAxis struct {
+	Cancelable
+	TargetWidget // TargetWidget will be set to the current focused widget
+	Timestamp
+	Gamepad
+	Axis  ebiten.StandardGamepadAxis
+	Value float64 // The value of the axis, from -1 to 1.
+	Delta float64 // How much the value changed since the last event.
+}
+
+// Activate is an event that is triggered when the focused element is activated without a pointer, such as by a gamepad's A button.
+type Activate struct {
+	Cancelable
+	TargetWidget
+	Timestamp
+}
+
+// Cancel is an event that is triggered when the user backs out of the focused element without a pointer, such as by a gamepad's B button. It is sent to the focused element and then to each of its parents until one cancels it.
+type Cancel struct {
+	Cancelable
+	TargetWidget
+	Timestamp
+}
@@ -0,0 +1,27 @@
+package events
+
+// Pinch is an event that is triggered when two touches move closer together or further apart. The Pointer's X and Y are the center between the touches.
+type Pinch struct {
+	Cancelable
+	TargetWidget
+	Timestamp
+	Pointer
+	Scale float64 // How much the distance between the touches changed since the last Pinch, such as 1.1 for a 10% increase.
+}
+
+// Rotate is an event that is triggered when two touches turn around each other. The Pointer's X and Y are the center between the touches.
+type Rotate struct {
+	Cancelable
+	TargetWidget
+	Timestamp
+	Pointer
+	Angle float64 // How far the touches turned since the last Rotate, in radians. Positive angles are clockwise on screen.
+}
+
+// Pan is an event that is triggered when the center between two touches moves. The Pointer's X and Y are the center, while DX and DY are how far it moved since the last Pan.
+type Pan struct {
+	Cancelable
+	TargetWidget
+	Timestamp
+	Pointer
+}
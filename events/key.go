@@ -15,6 +15,7 @@ type KeyPress struct {
 	Cancelable
 	TargetWidget // TargetWidget will be set to the current focused widget
 	Timestamp
+	Modifiers
 	Key    ebiten.Key
 	Repeat int
 }
@@ -24,6 +25,7 @@ type KeyRelease struct {
 	Cancelable
 	TargetWidget // TargetWidget will be set to the current focused widget
 	Timestamp
+	Modifiers
 	Duration // How long this move event has been happening.
 	Key      ebiten.Key
 }
@@ -33,5 +35,6 @@ type KeyInput struct {
 	Cancelable
 	TargetWidget // TargetWidget will be set to the current focused widget
 	Timestamp
+	Modifiers
 	Rune rune
 }
@@ -0,0 +1,9 @@
+package events
+
+// Modifiers is the state of the modifier keys when an event occurred. Either the left or right key counts as held.
+type Modifiers struct {
+	Shift bool
+	Ctrl  bool
+	Alt   bool
+	Meta  bool // The Command key on macOS or the Windows key elsewhere.
+}
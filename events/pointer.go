@@ -8,21 +8,53 @@ type Pointer struct {
 	RelativeX, RelativeY float64
 	ButtonID             int // The mouse button this represents if applicable.
 	TouchID              int // The touch this represents if applicable.
+	Modifiers
+}
+
+// PointerID identifies a pointer. Touches and mouse buttons are numbered separately, so a touch and a button can share an ID.
+type PointerID struct {
+	Touch bool // If the pointer is a touch rather than a mouse button.
+	ID    int  // The TouchID if the pointer is a touch, or the ButtonID otherwise.
+}
+
+// ID returns the ID used to track the pointer.
+func (p Pointer) ID() PointerID {
+	if p.TouchID > 0 { // I hope touches can't be 0...
+		return PointerID{Touch: true, ID: p.TouchID}
+	}
+	return PointerID{ID: p.ButtonID}
 }
 
 // Cancelable is an event that can be canceled. This is the case for all events.
 type Cancelable struct {
-	canceled bool
+	stopped   bool
+	prevented bool
 }
 
-// Cancel cancels the event.
+// Cancel cancels the event, which both stops its propagation and prevents its default action.
 func (c *Cancelable) Cancel() {
-	c.canceled = true
+	c.stopped = true
+	c.prevented = true
 }
 
-// Canceled returns true if the event has been canceled.
+// Canceled returns true if the event's propagation has been stopped, such as by Cancel.
 func (c *Cancelable) Canceled() bool {
-	return c.canceled
+	return c.stopped
+}
+
+// StopPropagation keeps the event from being sent to any further elements.
+func (c *Cancelable) StopPropagation() {
+	c.stopped = true
+}
+
+// PreventDefault keeps the Layout from performing the event's default action, such as focusing a pressed element or moving focus with Tab. The event still propagates.
+func (c *Cancelable) PreventDefault() {
+	c.prevented = true
+}
+
+// DefaultPrevented returns true if the event's default action has been prevented, such as by Cancel.
+func (c *Cancelable) DefaultPrevented() bool {
+	return c.prevented
 }
 
 // PointerMove is an event that is triggered when a pointer moves with an element or if an element was pressed and the pointer moves outside of its hit box.
@@ -50,12 +82,13 @@ type PointerOut struct {
 	Pointer
 }
 
-// PointerPress is an event that is triggered when a pointer has depressed an element.
+// PointerPress is an event that is triggered when a pointer has depressed an element. The element may capture the pointer.
 type PointerPress struct {
 	Cancelable
 	TargetWidget
 	Timestamp
 	Pointer
+	Capturable
 }
 
 // PointerRelease is an event that is triggered when a pointer has released an element.
@@ -75,3 +108,69 @@ type PointerPressed struct {
 	Duration // How long elapsed from press until release.
 	Pointer
 }
+
+// PointerWheel is an event that is triggered when the mouse wheel is turned over an element. The Pointer's DX and DY hold the wheel movement rather than the pointer's. It is dispatched to the topmost element under the pointer and bubbles up through its parents until one cancels it.
+type PointerWheel struct {
+	Cancelable
+	TargetWidget
+	Timestamp
+	Pointer
+}
+
+// PointerTap is an event that is triggered when an element is pressed and released without the pointer moving far or being held long enough to be a long press. Taps in quick succession near each other increase the Count, so a double tap has a Count of 2.
+type PointerTap struct {
+	Cancelable
+	TargetWidget
+	Timestamp
+	Duration // How long elapsed from press until release.
+	Pointer
+	Count int
+}
+
+// PointerDoubleClick is an event that is triggered along with the second PointerTap in quick succession.
+type PointerDoubleClick struct {
+	Cancelable
+	TargetWidget
+	Timestamp
+	Pointer
+}
+
+// PointerLongPress is an event that is triggered when a pointer is held over an element without moving far. A long press does not also cause a PointerTap.
+type PointerLongPress struct {
+	Cancelable
+	TargetWidget
+	Timestamp
+	Duration // How long the pointer has been held.
+	Pointer
+}
+
+// Capturable is a pointer event whose pointer can be captured by the element handling it.
+type Capturable struct {
+	capture bool
+}
+
+// CapturePointer captures the pointer for the element currently handling the event, so that the pointer's moves and releases are sent to the element until the pointer is released.
+func (c *Capturable) CapturePointer() {
+	c.capture = true
+}
+
+// CaptureRequested returns true if CapturePointer has been called.
+func (c *Capturable) CaptureRequested() bool {
+	return c.capture
+}
+
+// GotCapture is an event that is triggered when an element captures a pointer.
+type GotCapture struct {
+	Cancelable
+	TargetWidget
+	Timestamp
+	PointerID PointerID // The captured pointer.
+}
+
+// LostCapture is an event that is triggered when an element loses its capture of a pointer, such as when the pointer is released.
+type LostCapture struct {
+	Cancelable
+	TargetWidget
+	Timestamp
+	PointerID PointerID
+}
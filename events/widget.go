@@ -7,7 +7,32 @@ type Widget interface {
 	Draw(*ebiten.Image, *ebiten.DrawImageOptions)
 }
 
+// Phase is the phase of an event's dispatch through the element tree.
+type Phase int
+
+// Our dispatch phases.
+const (
+	PhaseNone    Phase = iota // The event is sent directly to an element rather than dispatched.
+	PhaseCapture              // The event is travelling from the root down to its target.
+	PhaseTarget               // The event is at its target.
+	PhaseBubble               // The event is travelling from its target back up to the root.
+)
+
 // TargetWidget is an event that has a target element.
 type TargetWidget struct {
-	Widget Widget
+	Widget Widget // The widget currently handling the event.
+	Target Widget // The widget the event was dispatched to, such as the topmost widget under a pointer or the focused widget.
+	Phase  Phase
+}
+
+// CurrentTarget returns the widget currently handling the event, which is the same as Widget.
+func (t *TargetWidget) CurrentTarget() Widget {
+	return t.Widget
+}
+
+// SetTarget is used by the Layout to update the event as it is dispatched.
+func (t *TargetWidget) SetTarget(target, current Widget, phase Phase) {
+	t.Target = target
+	t.Widget = current
+	t.Phase = phase
 }
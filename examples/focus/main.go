@@ -30,6 +30,7 @@ func (g *Game) Layout(outsideWidth, outsideHeight int) (int, int) {
 
 func main() {
 	g := &Game{}
+	g.layout.ArrowNavigation = true
 
 	g.layout.AddNode(rebui.Node{
 		Type:            "MyButton",
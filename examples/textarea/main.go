@@ -0,0 +1,69 @@
+package main
+
+import (
+	"log"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/kettek/rebui"
+	_ "github.com/kettek/rebui/defaults/font"
+	"github.com/kettek/rebui/widgets"
+)
+
+type Game struct {
+	layout rebui.Layout
+}
+
+func (g *Game) Update() error {
+	g.layout.Update()
+	return nil
+}
+
+func (g *Game) Draw(screen *ebiten.Image) {
+	g.layout.Draw(screen)
+}
+
+func (g *Game) Layout(outsideWidth, outsideHeight int) (int, int) {
+	return 320, 240
+}
+
+func main() {
+	g := &Game{}
+
+	chat := g.layout.AddNode(rebui.Node{
+		Type:            "Text",
+		Width:           "calc(100% - 20)",
+		Height:          "140",
+		X:               "10",
+		Y:               "10",
+		ForegroundColor: "white",
+		BackgroundColor: "black",
+		BorderColor:     "white",
+		TextWrap:        rebui.WrapWord,
+	})
+
+	node := g.layout.AddNode(rebui.Node{
+		Type:            "TextArea",
+		Width:           "calc(100% - 20)",
+		Height:          "70",
+		X:               "10",
+		Y:               "160",
+		ForegroundColor: "white",
+		BackgroundColor: "red",
+		BorderColor:     "white",
+		FocusIndex:      1,
+	})
+
+	var history string
+	node.Widget.(*widgets.TextArea).OnSubmit = func(text string) {
+		history += text + "\n"
+		chat.Widget.(*widgets.Text).AssignText(history)
+		node.Widget.(*widgets.TextArea).AssignText("")
+	}
+
+	ebiten.SetWindowSize(640, 480)
+	ebiten.SetWindowTitle("TextArea (Ctrl+Enter to send)")
+
+	if err := ebiten.RunGame(g); err != nil {
+		log.Fatal(err)
+	}
+}
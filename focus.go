@@ -0,0 +1,207 @@
+package rebui
+
+import (
+	"math"
+	"slices"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/kettek/rebui/events"
+	"github.com/kettek/rebui/widgets/getters"
+	"github.com/kettek/rebui/widgets/receivers"
+)
+
+// Focus focuses the given node, sending an Unfocus event to the previously focused node and a Focus event to the new one. Any ScrollWidgets containing the node are scrolled to show it. Passing nil is the same as calling Blur.
+func (l *Layout) Focus(n *Node) {
+	l.setFocus(n, events.Timestamp{Timestamp: l.now()}, events.Pointer{})
+	if n != nil {
+		l.scrollIntoView(n)
+	}
+}
+
+// FocusedNode returns the focused node, or nil if there is none.
+func (l *Layout) FocusedNode() *Node {
+	return l.focusedNode
+}
+
+// Blur unfocuses the focused node, if any.
+func (l *Layout) Blur() {
+	l.setFocus(nil, events.Timestamp{Timestamp: l.now()}, events.Pointer{})
+}
+
+// setFocus changes the focused node, sending Unfocus and Focus events as needed. The pointer is passed along to the Focus event if the focus was caused by a pointer.
+func (l *Layout) setFocus(n *Node, ts events.Timestamp, p events.Pointer) {
+	if l.focusedNode == n {
+		return
+	}
+	if l.focusedNode != nil {
+		unfocusEvent := &events.Unfocus{
+			Timestamp: ts,
+		}
+		retarget(unfocusEvent, l.focusedNode, l.focusedNode, events.PhaseTarget)
+		if l.focusedNode.OnUnfocus != nil {
+			l.focusedNode.OnUnfocus(unfocusEvent)
+		}
+		if hunfocus, ok := l.focusedNode.Widget.(receivers.Unfocus); ok {
+			hunfocus.HandleUnfocus(unfocusEvent)
+		}
+	}
+	l.focusedNode = n
+	if n != nil {
+		focusEvent := &events.Focus{
+			Timestamp: ts,
+			Pointer:   p,
+		}
+		retarget(focusEvent, n, n, events.PhaseTarget)
+		if n.OnFocus != nil {
+			n.OnFocus(focusEvent)
+		}
+		if hfocus, ok := n.Widget.(receivers.Focus); ok {
+			hfocus.HandleFocus(focusEvent)
+		}
+	}
+}
+
+// focusableNodes returns all visible and enabled nodes with a FocusIndex above 0, ordered by FocusIndex and then by declaration order.
+func (l *Layout) focusableNodes() (ns Nodes) {
+	var walk func(ns2 Nodes)
+	walk = func(ns2 Nodes) {
+		for _, n := range ns2 {
+			if n.Hidden {
+				continue
+			}
+			disabled := n.Disabled
+			if dg, ok := n.Widget.(getters.Disabled); ok {
+				disabled = dg.GetDisabled()
+			}
+			if n.FocusIndex > 0 && !disabled {
+				ns = append(ns, n)
+			}
+			walk(n.Children)
+		}
+	}
+	walk(l.Nodes)
+	slices.SortStableFunc(ns, func(a, b *Node) int {
+		return a.FocusIndex - b.FocusIndex
+	})
+	return
+}
+
+// FocusNext moves focus to the next focusable node, wrapping around to the first. If nothing is focused, the first focusable node is focused.
+func (l *Layout) FocusNext() {
+	l.focusStep(1)
+}
+
+// FocusPrevious moves focus to the previous focusable node, wrapping around to the last. If nothing is focused, the last focusable node is focused.
+func (l *Layout) FocusPrevious() {
+	l.focusStep(-1)
+}
+
+func (l *Layout) focusStep(dir int) {
+	ns := l.focusableNodes()
+	if len(ns) == 0 {
+		return
+	}
+	i := slices.Index(ns, l.focusedNode)
+	if i == -1 {
+		if dir > 0 {
+			i = len(ns) - 1
+		} else {
+			i = 0
+		}
+	}
+	l.Focus(ns[(i+dir+len(ns))%len(ns)])
+}
+
+// FocusDirection moves focus to the nearest focusable node in the given direction from the focused node, as determined by their on-screen positions. If nothing is focused, the first focusable node is focused.
+func (l *Layout) FocusDirection(dx, dy int) {
+	if l.focusedNode == nil {
+		l.FocusNext()
+		return
+	}
+	fromX, fromY := l.focusedNode.screenCenter()
+
+	var best *Node
+	bestScore := math.Inf(1)
+	for _, n := range l.focusableNodes() {
+		if n == l.focusedNode {
+			continue
+		}
+		x, y := n.screenCenter()
+		// Distance along the direction must be positive, with distance across it weighted so that nodes in line are preferred.
+		along := (x-fromX)*float64(dx) + (y-fromY)*float64(dy)
+		across := math.Abs((x-fromX)*float64(dy) + (y-fromY)*float64(dx))
+		if along <= 0 {
+			continue
+		}
+		if score := along + across*2; score < bestScore {
+			best, bestScore = n, score
+		}
+	}
+	if best != nil {
+		l.Focus(best)
+	}
+}
+
+// screenCenter returns the center of the node on screen, accounting for any scrolling.
+func (n *Node) screenCenter() (x, y float64) {
+	dx, dy, _, _ := n.view()
+	return n.x + dx + n.width/2, n.y + dy + n.height/2
+}
+
+// scrollIntoView scrolls any ScrollWidgets containing the node, innermost first, so that as much of the node as possible is visible.
+func (l *Layout) scrollIntoView(n *Node) {
+	x, y, w, h := n.x, n.y, n.width, n.height
+	for p := n.Parent; p != nil; p = p.Parent {
+		sw, ok := p.Widget.(ScrollWidget)
+		if !ok {
+			continue
+		}
+		sx, sy := sw.ScrollOffset()
+		// The node's position within the scrolled view.
+		vx, vy := x-sx, y-sy
+		var dx, dy float64
+		if vx+w > p.x+p.width {
+			dx = vx + w - (p.x + p.width)
+		}
+		if vx+dx < p.x {
+			dx = vx - p.x
+		}
+		if vy+h > p.y+p.height {
+			dy = vy + h - (p.y + p.height)
+		}
+		if vy+dy < p.y {
+			dy = vy - p.y
+		}
+		sw.ScrollBy(dx, dy)
+		// Outer views need to show the area of this view that contains the node.
+		x, y, w, h = max(p.x, x-sx-dx), max(p.y, y-sy-dy), min(w, p.width), min(h, p.height)
+	}
+}
+
+// processFocusKey moves focus in response to a key press whose default action was not prevented. Tab and Shift+Tab move through nodes by FocusIndex, while the arrow keys move spatially if ArrowNavigation is set.
+func (l *Layout) processFocusKey(evt *events.KeyPress) {
+	switch evt.Key {
+	case ebiten.KeyTab:
+		if evt.Shift {
+			l.FocusPrevious()
+		} else {
+			l.FocusNext()
+		}
+	case ebiten.KeyArrowLeft:
+		if l.ArrowNavigation {
+			l.FocusDirection(-1, 0)
+		}
+	case ebiten.KeyArrowRight:
+		if l.ArrowNavigation {
+			l.FocusDirection(1, 0)
+		}
+	case ebiten.KeyArrowUp:
+		if l.ArrowNavigation {
+			l.FocusDirection(0, -1)
+		}
+	case ebiten.KeyArrowDown:
+		if l.ArrowNavigation {
+			l.FocusDirection(0, 1)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package rebui_test
+
+import (
+	"testing"
+
+	"github.com/kettek/rebui"
+	"github.com/kettek/rebui/rebuitest"
+	_ "github.com/kettek/rebui/widgets"
+)
+
+func TestRemoveFocusedNode(t *testing.T) {
+	h := rebuitest.New(t, 320, 240,
+		rebui.Node{
+			Type:   "Area",
+			ID:     "panel",
+			Width:  "100",
+			Height: "100",
+			Children: rebui.Nodes{
+				{
+					Type:       "Area",
+					ID:         "field",
+					Width:      "50",
+					Height:     "50",
+					FocusIndex: 1,
+				},
+			},
+		},
+		rebui.Node{
+			Type:   "Area",
+			ID:     "other",
+			X:      "after panel",
+			Width:  "100",
+			Height: "100",
+		},
+	)
+
+	field := h.Node("field")
+	var unfocused int
+	field.OnUnfocus = func(evt rebui.EventUnfocus) {
+		unfocused++
+	}
+
+	h.Layout.Focus(field)
+	h.Layout.RemoveNode(h.Node("other"))
+	if h.Layout.FocusedNode() != field || unfocused != 0 {
+		t.Fatalf("expected removing another node to keep the focus, got %d unfocuses", unfocused)
+	}
+
+	h.Layout.RemoveNode(h.Node("panel"))
+	if h.Layout.FocusedNode() != nil {
+		t.Error("expected removing the focused node's parent to unfocus it")
+	}
+	if unfocused != 1 {
+		t.Errorf("expected the removed node to be sent an Unfocus event, got %d", unfocused)
+	}
+}
@@ -0,0 +1,321 @@
+package rebui
+
+import (
+	"math"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/kettek/rebui/events"
+	"github.com/kettek/rebui/widgets/receivers"
+)
+
+// GamepadAction is a navigation action that gamepad input can be mapped to.
+type GamepadAction int
+
+// Our gamepad actions.
+const (
+	GamepadActionNone     GamepadAction = iota
+	GamepadActionUp                     // Moves focus upward, as the up arrow does with ArrowNavigation.
+	GamepadActionDown                   // Moves focus downward.
+	GamepadActionLeft                   // Moves focus leftward.
+	GamepadActionRight                  // Moves focus rightward.
+	GamepadActionNext                   // Moves focus to the next node, as Tab does.
+	GamepadActionPrevious               // Moves focus to the previous node, as Shift+Tab does.
+	GamepadActionActivate               // Sends an Activate event to the focused node.
+	GamepadActionCancel                 // Sends a Cancel event to the focused node and its parents.
+)
+
+// GamepadMapping maps gamepad input to navigation actions. Button actions only occur if the button press's default action is not prevented.
+type GamepadMapping struct {
+	Buttons        map[ebiten.StandardGamepadButton]GamepadAction
+	HorizontalAxis ebiten.StandardGamepadAxis // The axis that moves focus horizontally, such as a stick.
+	VerticalAxis   ebiten.StandardGamepadAxis // The axis that moves focus vertically.
+	AxisThreshold  float64                    // How far an axis must be pushed to move focus. If 0, axes do not move focus.
+}
+
+// DefaultGamepadMapping returns a mapping where the D-pad and left stick move focus, the front top buttons move to the previous and next nodes, the bottom face button (A) activates, and the right face button (B) cancels.
+func DefaultGamepadMapping() *GamepadMapping {
+	return &GamepadMapping{
+		Buttons: map[ebiten.StandardGamepadButton]GamepadAction{
+			ebiten.StandardGamepadButtonLeftTop:       GamepadActionUp,
+			ebiten.StandardGamepadButtonLeftBottom:    GamepadActionDown,
+			ebiten.StandardGamepadButtonLeftLeft:      GamepadActionLeft,
+			ebiten.StandardGamepadButtonLeftRight:     GamepadActionRight,
+			ebiten.StandardGamepadButtonFrontTopRight: GamepadActionNext,
+			ebiten.StandardGamepadButtonFrontTopLeft:  GamepadActionPrevious,
+			ebiten.StandardGamepadButtonRightBottom:   GamepadActionActivate,
+			ebiten.StandardGamepadButtonRightRight:    GamepadActionCancel,
+		},
+		HorizontalAxis: ebiten.StandardGamepadAxisLeftStickHorizontal,
+		VerticalAxis:   ebiten.StandardGamepadAxisLeftStickVertical,
+		AxisThreshold:  0.5,
+	}
+}
+
+// gamepadAxisEpsilon is how much an axis must change to send a GamepadAxis event.
+const gamepadAxisEpsilon = 0.01
+
+type gamepadButton struct {
+	id     ebiten.GamepadID
+	button ebiten.StandardGamepadButton
+	time   time.Time // When this button press was started.
+	next   time.Time // Next time to repeat this button.
+	count  int
+}
+
+type gamepadAxis struct {
+	id   ebiten.GamepadID
+	axis ebiten.StandardGamepadAxis
+}
+
+// gamepadStick is the direction a gamepad's navigation axes are held in.
+type gamepadStick struct {
+	dx, dy int
+	next   time.Time // Next time to repeat focus movement.
+}
+
+func (l *Layout) getGamepadEvents() (evts []Event) {
+	ts := l.now()
+	input := l.input()
+	ids := input.AppendGamepadIDs(nil)
+
+	var pressedButtons []gamepadButton
+	var releasedButtons []gamepadButton
+	var newPressedButtons []gamepadButton
+	var repeatButtons []gamepadButton
+
+	for _, id := range ids {
+		for b := ebiten.StandardGamepadButton(0); b <= ebiten.StandardGamepadButtonMax; b++ {
+			if input.IsStandardGamepadButtonPressed(id, b) {
+				pressedButtons = append(pressedButtons, gamepadButton{id: id, button: b, time: ts, next: ts.Add(500 * time.Millisecond)})
+			}
+		}
+	}
+
+	for _, b := range l.gamepadButtons {
+		exists := false
+		for _, b2 := range pressedButtons {
+			if b.id == b2.id && b.button == b2.button {
+				exists = true
+				break
+			}
+		}
+		if !exists {
+			releasedButtons = append(releasedButtons, b)
+		}
+	}
+	for i, b := range pressedButtons {
+		exists := false
+		var prevButton gamepadButton
+		for _, b2 := range l.gamepadButtons {
+			if b.id == b2.id && b.button == b2.button {
+				prevButton = b2
+				exists = true
+				break
+			}
+		}
+		if !exists {
+			newPressedButtons = append(newPressedButtons, b)
+		} else {
+			if prevButton.next.Before(ts) {
+				repeatButtons = append(repeatButtons, gamepadButton{
+					id:     prevButton.id,
+					button: prevButton.button,
+					time:   ts,
+					count:  prevButton.count + 1,
+				})
+				prevButton.next = ts.Add(50 * time.Millisecond)
+				prevButton.count++
+			}
+			pressedButtons[i] = prevButton
+		}
+	}
+	for _, b := range newPressedButtons {
+		evts = append(evts, &events.GamepadButtonPress{
+			Timestamp: events.Timestamp{Timestamp: ts},
+			Gamepad:   events.Gamepad{GamepadID: b.id},
+			Button:    b.button,
+		})
+	}
+	for _, b := range releasedButtons {
+		evts = append(evts, &events.GamepadButtonRelease{
+			Timestamp: events.Timestamp{Timestamp: ts},
+			Duration:  events.Duration{Duration: ts.Sub(b.time)},
+			Gamepad:   events.Gamepad{GamepadID: b.id},
+			Button:    b.button,
+		})
+	}
+	for _, b := range repeatButtons {
+		evts = append(evts, &events.GamepadButtonPress{
+			Timestamp: events.Timestamp{Timestamp: ts},
+			Gamepad:   events.Gamepad{GamepadID: b.id},
+			Button:    b.button,
+			Repeat:    b.count,
+		})
+	}
+	l.gamepadButtons = pressedButtons
+
+	// Axes are only reported when they change, and are forgotten once their gamepad disconnects.
+	axes := make(map[gamepadAxis]float64)
+	for _, id := range ids {
+		for a := ebiten.StandardGamepadAxis(0); a <= ebiten.StandardGamepadAxisMax; a++ {
+			k := gamepadAxis{id, a}
+			v := input.StandardGamepadAxisValue(id, a)
+			last := l.gamepadAxes[k]
+			if math.Abs(v-last) < gamepadAxisEpsilon && (v != 0 || last == 0) {
+				axes[k] = last
+				continue
+			}
+			axes[k] = v
+			evts = append(evts, &events.GamepadAxis{
+				Timestamp: events.Timestamp{Timestamp: ts},
+				Gamepad:   events.Gamepad{GamepadID: id},
+				Axis:      a,
+				Value:     v,
+				Delta:     v - last,
+			})
+		}
+	}
+	l.gamepadAxes = axes
+
+	return
+}
+
+// processGamepadStick moves focus while the mapped navigation axes of a gamepad are pushed past the mapping's threshold, repeating as keys do.
+func (l *Layout) processGamepadStick() {
+	m := l.Gamepad
+	if m == nil || m.AxisThreshold <= 0 {
+		l.gamepadSticks = nil
+		return
+	}
+	ts := l.now()
+	sticks := make(map[ebiten.GamepadID]gamepadStick)
+	for k, v := range l.gamepadAxes {
+		if k.axis != m.HorizontalAxis {
+			continue
+		}
+		h, v2 := v, l.gamepadAxes[gamepadAxis{k.id, m.VerticalAxis}]
+		var dx, dy int
+		if math.Abs(h) >= math.Abs(v2) && math.Abs(h) >= m.AxisThreshold {
+			dx = int(math.Copysign(1, h))
+		} else if math.Abs(v2) >= m.AxisThreshold {
+			dy = int(math.Copysign(1, v2))
+		} else {
+			continue
+		}
+		stick, held := l.gamepadSticks[k.id]
+		if !held || stick.dx != dx || stick.dy != dy {
+			stick = gamepadStick{dx: dx, dy: dy, next: ts.Add(500 * time.Millisecond)}
+			l.FocusDirection(dx, dy)
+		} else if stick.next.Before(ts) {
+			stick.next = ts.Add(50 * time.Millisecond)
+			l.FocusDirection(dx, dy)
+		}
+		sticks[k.id] = stick
+	}
+	l.gamepadSticks = sticks
+}
+
+// processGamepadAction performs the action mapped to a gamepad button press whose default action was not prevented. Only directional actions repeat while the button is held.
+func (l *Layout) processGamepadAction(evt *events.GamepadButtonPress) {
+	if l.Gamepad == nil {
+		return
+	}
+	action := l.Gamepad.Buttons[evt.Button]
+	switch action {
+	case GamepadActionUp:
+		l.FocusDirection(0, -1)
+	case GamepadActionDown:
+		l.FocusDirection(0, 1)
+	case GamepadActionLeft:
+		l.FocusDirection(-1, 0)
+	case GamepadActionRight:
+		l.FocusDirection(1, 0)
+	}
+	if evt.Repeat > 0 {
+		return
+	}
+	switch action {
+	case GamepadActionNext:
+		l.FocusNext()
+	case GamepadActionPrevious:
+		l.FocusPrevious()
+	case GamepadActionActivate:
+		l.Activate()
+	case GamepadActionCancel:
+		l.CancelFocus()
+	}
+}
+
+// Activate activates the focused node, as with ActivateNode.
+func (l *Layout) Activate() {
+	l.ActivateNode(l.focusedNode)
+}
+
+// ActivateNode sends an Activate event to the given node. If the node has neither an OnActivate hook nor a widget that receives Activate events, a PointerPressed event at its center is dispatched to it instead, so that widgets such as buttons can be activated without changes. The PointerPressed event has a ButtonID of -1.
+func (l *Layout) ActivateNode(n *Node) {
+	if n == nil {
+		return
+	}
+	ts := events.Timestamp{Timestamp: l.now()}
+	handled := false
+	activateEvent := &events.Activate{
+		Timestamp: ts,
+	}
+	retarget(activateEvent, n, n, events.PhaseTarget)
+	if n.OnActivate != nil {
+		n.OnActivate(activateEvent)
+		handled = true
+	}
+	if !activateEvent.Canceled() {
+		if hactivate, ok := n.Widget.(receivers.Activate); ok {
+			hactivate.HandleActivate(activateEvent)
+			handled = true
+		}
+	}
+	if handled {
+		return
+	}
+
+	x, y := n.screenCenter()
+	pointerPressedEvent := &events.PointerPressed{
+		Timestamp: ts,
+		Pointer: events.Pointer{
+			X:        x,
+			Y:        y,
+			ButtonID: -1,
+		},
+	}
+	l.dispatchEvent(n, pointerPressedEvent, func(n *Node) {
+		if n.OnPointerPressed != nil {
+			n.OnPointerPressed(pointerPressedEvent)
+		}
+		if pointerPressedEvent.Canceled() {
+			return
+		}
+		if hpress, ok := n.Widget.(receivers.PointerPressed); ok {
+			hpress.HandlePointerPressed(pointerPressedEvent)
+		}
+	})
+}
+
+// CancelFocus dispatches a Cancel event to the focused node, so that it bubbles up through its parents until one cancels it.
+func (l *Layout) CancelFocus() {
+	if l.focusedNode == nil {
+		return
+	}
+	cancelEvent := &events.Cancel{
+		Timestamp: events.Timestamp{Timestamp: l.now()},
+	}
+	l.dispatchEvent(l.focusedNode, cancelEvent, func(n *Node) {
+		if n.OnCancel != nil {
+			n.OnCancel(cancelEvent)
+		}
+		if cancelEvent.Canceled() {
+			return
+		}
+		if hcancel, ok := n.Widget.(receivers.Cancel); ok {
+			hcancel.HandleCancel(cancelEvent)
+		}
+	})
+}
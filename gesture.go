@@ -0,0 +1,275 @@
+package rebui
+
+import (
+	"math"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/kettek/rebui/events"
+	"github.com/kettek/rebui/widgets/receivers"
+)
+
+// Default gesture thresholds, used when the Layout's are 0.
+const (
+	defaultLongPressDuration = 500 * time.Millisecond
+	defaultMultiTapInterval  = 400 * time.Millisecond
+	defaultTapSlop           = 8
+)
+
+// pointerGesture tracks a pressed pointer for recognizing taps and long presses.
+type pointerGesture struct {
+	node        *Node // The node the press was dispatched to.
+	draggable   *Node // The node to drag if the pointer moves far enough, if any.
+	time        time.Time
+	pointer     events.Pointer // The pointer as of its latest press or move.
+	movement    float64        // How far the pointer has moved since it was pressed, as with touch movement.
+	longPressed bool
+}
+
+// pointerTap is the most recent tap, used to count taps in quick succession.
+type pointerTap struct {
+	node    *Node
+	pointer events.PointerID // Touches have a new ID each time, so any touch counts as the same pointer.
+	time    time.Time
+	x, y    float64
+	count   int
+}
+
+func (l *Layout) longPressDuration() time.Duration {
+	return fallback(l.LongPressDuration, defaultLongPressDuration)
+}
+
+func (l *Layout) multiTapInterval() time.Duration {
+	return fallback(l.MultiTapInterval, defaultMultiTapInterval)
+}
+
+func (l *Layout) tapSlop() float64 {
+	return fallback(l.TapSlop, defaultTapSlop)
+}
+
+// startGesture begins tracking a press that was dispatched to the given node.
+func (l *Layout) startGesture(n *Node, evt *events.PointerPress) {
+	if l.gestures == nil {
+		l.gestures = make(map[events.PointerID]*pointerGesture)
+	}
+	g := &pointerGesture{
+		node:    n,
+		time:    evt.Timestamp.Timestamp,
+		pointer: evt.Pointer,
+	}
+	// Only the primary mouse button and touches drag.
+	if id := evt.ID(); id.Touch || id.ID == int(ebiten.MouseButtonLeft) {
+		g.draggable = draggableNode(n)
+	}
+	l.gestures[evt.ID()] = g
+}
+
+// moveGesture accumulates the movement of a tracked press, starting a drag once it moves far enough from a draggable node, or moving the drag it started.
+func (l *Layout) moveGesture(evt *events.PointerMove) {
+	g, ok := l.gestures[evt.ID()]
+	if !ok {
+		return
+	}
+	g.movement += math.Abs(evt.DX) + math.Abs(evt.DY)
+	g.pointer.X, g.pointer.Y = evt.X, evt.Y
+	if d := l.drag; d != nil {
+		if d.pid == evt.ID() {
+			l.dragMove(d, evt.Pointer, evt.Timestamp)
+		}
+		return
+	}
+	if g.draggable != nil && g.movement > l.tapSlop() {
+		l.startDrag(g, evt)
+	}
+}
+
+// endGesture stops tracking a press, dispatching a PointerTap, and a PointerDoubleClick for the second tap in succession, to the pressed node if the press was a tap.
+func (l *Layout) endGesture(pressed *Node, evt *events.PointerRelease) {
+	pid := evt.ID()
+	g, ok := l.gestures[pid]
+	if !ok {
+		return
+	}
+	delete(l.gestures, pid)
+	if pressed == nil || g.longPressed || g.movement > l.tapSlop() {
+		return
+	}
+
+	ts := evt.Timestamp.Timestamp
+	last := l.lastTap
+	if last.count > 0 && last.node == pressed && last.pointer.Touch == pid.Touch && (pid.Touch || last.pointer == pid) &&
+		ts.Sub(last.time) <= l.multiTapInterval() && math.Hypot(evt.X-last.x, evt.Y-last.y) <= l.tapSlop() {
+		l.lastTap.count++
+	} else {
+		l.lastTap = pointerTap{node: pressed, pointer: pid, count: 1}
+	}
+	l.lastTap.time, l.lastTap.x, l.lastTap.y = ts, evt.X, evt.Y
+
+	pointerTapEvent := &events.PointerTap{
+		Duration:  evt.Duration,
+		Timestamp: evt.Timestamp,
+		Pointer:   evt.Pointer,
+		Count:     l.lastTap.count,
+	}
+	l.dispatchEvent(pressed, pointerTapEvent, func(n *Node) {
+		if n.OnPointerTap != nil {
+			n.OnPointerTap(pointerTapEvent)
+		}
+		if pointerTapEvent.Canceled() {
+			return
+		}
+		if htap, ok := n.Widget.(receivers.PointerTap); ok {
+			htap.HandlePointerTap(pointerTapEvent)
+		}
+	})
+
+	if l.lastTap.count != 2 {
+		return
+	}
+	pointerDoubleClickEvent := &events.PointerDoubleClick{
+		Timestamp: evt.Timestamp,
+		Pointer:   evt.Pointer,
+	}
+	l.dispatchEvent(pressed, pointerDoubleClickEvent, func(n *Node) {
+		if n.OnPointerDoubleClick != nil {
+			n.OnPointerDoubleClick(pointerDoubleClickEvent)
+		}
+		if pointerDoubleClickEvent.Canceled() {
+			return
+		}
+		if hclick, ok := n.Widget.(receivers.PointerDoubleClick); ok {
+			hclick.HandlePointerDoubleClick(pointerDoubleClickEvent)
+		}
+	})
+}
+
+// processLongPresses dispatches a PointerLongPress to the node of each press that has been held long enough without moving too far.
+func (l *Layout) processLongPresses() {
+	ts := l.now()
+	for _, g := range l.gestures {
+		if g.longPressed || g.movement > l.tapSlop() || ts.Sub(g.time) < l.longPressDuration() {
+			continue
+		}
+		g.longPressed = true
+		pointerLongPressEvent := &events.PointerLongPress{
+			Timestamp: events.Timestamp{Timestamp: ts},
+			Duration:  events.Duration{Duration: ts.Sub(g.time)},
+			Pointer:   g.pointer,
+		}
+		l.dispatchEvent(g.node, pointerLongPressEvent, func(n *Node) {
+			if n.OnPointerLongPress != nil {
+				n.OnPointerLongPress(pointerLongPressEvent)
+			}
+			if pointerLongPressEvent.Canceled() {
+				return
+			}
+			if hpress, ok := n.Widget.(receivers.PointerLongPress); ok {
+				hpress.HandlePointerLongPress(pointerLongPressEvent)
+			}
+		})
+	}
+}
+
+// touchGesture tracks the first two active touches for recognizing pinches, rotations, and pans.
+type touchGesture struct {
+	node     *Node // The node under the center of the touches when the gesture began.
+	ids      [2]ebiten.TouchID
+	x, y     float64 // The center between the touches.
+	distance float64
+	angle    float64
+}
+
+// touchGestureOf returns the center, distance, and angle between two touches.
+func touchGestureOf(a, b touch) (x, y, distance, angle float64) {
+	ax, ay, bx, by := float64(a.x), float64(a.y), float64(b.x), float64(b.y)
+	return (ax + bx) / 2, (ay + by) / 2, math.Hypot(bx-ax, by-ay), math.Atan2(by-ay, bx-ax)
+}
+
+// processTouchGesture dispatches Pinch, Rotate, and Pan events as the first two active touches move. The events are sent to the node that was under the center of the touches when the second touch began, for as long as both remain.
+func (l *Layout) processTouchGesture() {
+	if len(l.activeTouches) < 2 {
+		l.touchGesture = nil
+		return
+	}
+	a, b := l.activeTouches[0], l.activeTouches[1]
+	x, y, distance, angle := touchGestureOf(a, b)
+	g := l.touchGesture
+	if g == nil || g.ids != [2]ebiten.TouchID{a.id, b.id} {
+		l.touchGesture = &touchGesture{
+			node:     l.hitTarget(x, y),
+			ids:      [2]ebiten.TouchID{a.id, b.id},
+			x:        x,
+			y:        y,
+			distance: distance,
+			angle:    angle,
+		}
+		return
+	}
+	if g.node == nil {
+		return
+	}
+
+	ts := events.Timestamp{Timestamp: l.now()}
+	pointer := events.Pointer{
+		X:         x,
+		Y:         y,
+		DX:        x - g.x,
+		DY:        y - g.y,
+		TouchID:   int(a.id),
+		Modifiers: l.modifiers,
+	}
+	if distance != g.distance && g.distance > 0 {
+		pinchEvent := &events.Pinch{
+			Timestamp: ts,
+			Pointer:   pointer,
+			Scale:     distance / g.distance,
+		}
+		l.dispatchEvent(g.node, pinchEvent, func(n *Node) {
+			if n.OnPinch != nil {
+				n.OnPinch(pinchEvent)
+			}
+			if pinchEvent.Canceled() {
+				return
+			}
+			if hpinch, ok := n.Widget.(receivers.Pinch); ok {
+				hpinch.HandlePinch(pinchEvent)
+			}
+		})
+	}
+	if delta := math.Remainder(angle-g.angle, 2*math.Pi); delta != 0 {
+		rotateEvent := &events.Rotate{
+			Timestamp: ts,
+			Pointer:   pointer,
+			Angle:     delta,
+		}
+		l.dispatchEvent(g.node, rotateEvent, func(n *Node) {
+			if n.OnRotate != nil {
+				n.OnRotate(rotateEvent)
+			}
+			if rotateEvent.Canceled() {
+				return
+			}
+			if hrotate, ok := n.Widget.(receivers.Rotate); ok {
+				hrotate.HandleRotate(rotateEvent)
+			}
+		})
+	}
+	if pointer.DX != 0 || pointer.DY != 0 {
+		panEvent := &events.Pan{
+			Timestamp: ts,
+			Pointer:   pointer,
+		}
+		l.dispatchEvent(g.node, panEvent, func(n *Node) {
+			if n.OnPan != nil {
+				n.OnPan(panEvent)
+			}
+			if panEvent.Canceled() {
+				return
+			}
+			if hpan, ok := n.Widget.(receivers.Pan); ok {
+				hpan.HandlePan(panEvent)
+			}
+		})
+	}
+	g.x, g.y, g.distance, g.angle = x, y, distance, angle
+}
@@ -0,0 +1,170 @@
+package rebui_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/kettek/rebui"
+	"github.com/kettek/rebui/rebuitest"
+	_ "github.com/kettek/rebui/widgets"
+)
+
+func newGestureHarness(t *testing.T) *rebuitest.Harness {
+	return rebuitest.New(t, 320, 240,
+		rebui.Node{
+			Type:   "Area",
+			ID:     "a",
+			Width:  "100",
+			Height: "100",
+		},
+		rebui.Node{
+			Type:   "Area",
+			ID:     "b",
+			X:      "after a",
+			Width:  "100",
+			Height: "100",
+		},
+	)
+}
+
+func TestTapCount(t *testing.T) {
+	h := newGestureHarness(t)
+
+	var counts []int
+	var doubleClicks int
+	a := h.Node("a")
+	a.OnPointerTap = func(evt rebui.EventPointerTap) {
+		counts = append(counts, evt.Count)
+	}
+	a.OnPointerDoubleClick = func(evt rebui.EventPointerDoubleClick) {
+		doubleClicks++
+	}
+
+	h.Click("a")
+	h.Click("a")
+	h.Click("a")
+	if len(counts) != 3 || counts[0] != 1 || counts[1] != 2 || counts[2] != 3 {
+		t.Errorf("expected taps counting 1, 2, and 3, got %v", counts)
+	}
+	if doubleClicks != 1 {
+		t.Errorf("expected only the second tap to double-click, got %d", doubleClicks)
+	}
+
+	tests := []struct {
+		name   string
+		before func()
+		count  int
+	}{
+		{"within the interval", func() {}, 4},
+		{"after the interval", func() { h.Wait(500 * time.Millisecond) }, 1},
+		{"upon another node", func() { h.Click("b") }, 1},
+		{"with another button", func() { h.ClickButton("a", ebiten.MouseButtonRight) }, 1},
+		{"after a touch", func() { h.Tap("a", 1) }, 1},
+	}
+	for _, tt := range tests {
+		tt.before()
+		counts = counts[:0]
+		h.Click("a")
+		if len(counts) != 1 || counts[0] != tt.count {
+			t.Errorf("%s: expected a tap counting %d, got %v", tt.name, tt.count, counts)
+		}
+	}
+
+	// Touches have a new ID each time, but still count as the same pointer.
+	counts = counts[:0]
+	h.Tap("a", 1)
+	h.Tap("a", 2)
+	if len(counts) != 2 || counts[1] != 2 {
+		t.Errorf("expected successive touches to count as successive taps, got %v", counts)
+	}
+}
+
+func TestTapSlop(t *testing.T) {
+	h := newGestureHarness(t)
+
+	var taps int
+	h.Node("a").OnPointerTap = func(evt rebui.EventPointerTap) {
+		taps++
+	}
+
+	h.Drag("a", 4, 4, 2)
+	if taps != 1 {
+		t.Errorf("expected a press that moved within the slop to tap, got %d", taps)
+	}
+	h.Drag("a", 20, 0, 2)
+	if taps != 1 {
+		t.Errorf("expected a press that moved beyond the slop not to tap, got %d", taps)
+	}
+}
+
+func TestLongPress(t *testing.T) {
+	h := newGestureHarness(t)
+
+	var longPresses, taps int
+	var held time.Duration
+	a := h.Node("a")
+	a.OnPointerLongPress = func(evt rebui.EventPointerLongPress) {
+		longPresses++
+		held = evt.Duration.Duration
+	}
+	a.OnPointerTap = func(evt rebui.EventPointerTap) {
+		taps++
+	}
+
+	h.Press("a", ebiten.MouseButtonLeft)
+	h.Wait(400 * time.Millisecond)
+	if longPresses != 0 {
+		t.Errorf("expected no long press before 500ms, got %d", longPresses)
+	}
+	h.Wait(200 * time.Millisecond)
+	if longPresses != 1 || held < 500*time.Millisecond {
+		t.Errorf("expected a long press after 500ms, got %d after %v", longPresses, held)
+	}
+	h.Wait(time.Second)
+	if longPresses != 1 {
+		t.Errorf("expected a single long press while held, got %d", longPresses)
+	}
+	h.Release(ebiten.MouseButtonLeft)
+	if taps != 0 {
+		t.Errorf("expected a long press not to tap, got %d", taps)
+	}
+
+	// Moving beyond the slop is not a long press.
+	longPresses = 0
+	h.Press("a", ebiten.MouseButtonLeft)
+	x, y := h.Rect("a").Center()
+	h.MoveTo(x+20, y)
+	h.Wait(time.Second)
+	h.Release(ebiten.MouseButtonLeft)
+	if longPresses != 0 {
+		t.Errorf("expected no long press once moved, got %d", longPresses)
+	}
+
+	h.Layout.LongPressDuration = 100 * time.Millisecond
+	h.Press("a", ebiten.MouseButtonLeft)
+	h.Wait(150 * time.Millisecond)
+	h.Release(ebiten.MouseButtonLeft)
+	if longPresses != 1 {
+		t.Errorf("expected a long press after the layout's LongPressDuration, got %d", longPresses)
+	}
+}
+
+func TestGestureTouchAndButton(t *testing.T) {
+	h := newGestureHarness(t)
+
+	taps := map[string]int{}
+	for _, id := range []string{"a", "b"} {
+		h.Node(id).OnPointerTap = func(evt rebui.EventPointerTap) {
+			taps[id]++
+		}
+	}
+
+	// A touch may have the same number as a held mouse button, but its gesture is separate.
+	h.Press("a", ebiten.MouseButtonRight)
+	h.Tap("b", ebiten.TouchID(ebiten.MouseButtonRight))
+	h.Release(ebiten.MouseButtonRight)
+	if taps["a"] != 1 || taps["b"] != 1 {
+		t.Errorf("expected both a and b to be tapped, got %v", taps)
+	}
+}
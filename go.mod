@@ -3,7 +3,9 @@ module github.com/kettek/rebui
 go 1.24.0
 
 require (
+	github.com/go-text/typesetting v0.2.0
 	github.com/hajimehoshi/ebiten/v2 v2.8.6
+	github.com/kettek/tokenizer v0.0.0-20251125082402-ee2a4ae6a06f
 	golang.design/x/clipboard v0.7.0
 	gopkg.in/yaml.v3 v3.0.1
 )
@@ -12,9 +14,7 @@ require (
 	github.com/ebitengine/gomobile v0.0.0-20240911145611-4856209ac325 // indirect
 	github.com/ebitengine/hideconsole v1.0.0 // indirect
 	github.com/ebitengine/purego v0.8.0 // indirect
-	github.com/go-text/typesetting v0.2.0 // indirect
 	github.com/jezek/xgb v1.1.1 // indirect
-	github.com/kettek/tokenizer v0.0.0-20251125082402-ee2a4ae6a06f // indirect
 	golang.org/x/exp v0.0.0-20190731235908-ec7cb31e5a56 // indirect
 	golang.org/x/image v0.20.0 // indirect
 	golang.org/x/mobile v0.0.0-20230301163155-e0f57694e12c // indirect
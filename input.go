@@ -0,0 +1,306 @@
+package rebui
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// InputSource provides the raw input state that a Layout polls during Update.
+type InputSource interface {
+	CursorPosition() (x, y int)
+	IsMouseButtonPressed(ebiten.MouseButton) bool
+	AppendTouchIDs([]ebiten.TouchID) []ebiten.TouchID
+	TouchPosition(ebiten.TouchID) (x, y int)
+	AppendPressedKeys([]ebiten.Key) []ebiten.Key
+	AppendInputChars([]rune) []rune
+	Wheel() (x, y float64)
+	AppendGamepadIDs([]ebiten.GamepadID) []ebiten.GamepadID
+	IsStandardGamepadButtonPressed(ebiten.GamepadID, ebiten.StandardGamepadButton) bool
+	StandardGamepadAxisValue(ebiten.GamepadID, ebiten.StandardGamepadAxis) float64
+}
+
+// EbitenInput is the default InputSource, which polls Ebitengine's global input state.
+type EbitenInput struct{}
+
+// CursorPosition returns ebiten.CursorPosition.
+func (EbitenInput) CursorPosition() (x, y int) {
+	return ebiten.CursorPosition()
+}
+
+// IsMouseButtonPressed returns ebiten.IsMouseButtonPressed.
+func (EbitenInput) IsMouseButtonPressed(mb ebiten.MouseButton) bool {
+	return ebiten.IsMouseButtonPressed(mb)
+}
+
+// AppendTouchIDs returns ebiten.AppendTouchIDs.
+func (EbitenInput) AppendTouchIDs(ids []ebiten.TouchID) []ebiten.TouchID {
+	return ebiten.AppendTouchIDs(ids)
+}
+
+// TouchPosition returns ebiten.TouchPosition.
+func (EbitenInput) TouchPosition(id ebiten.TouchID) (x, y int) {
+	return ebiten.TouchPosition(id)
+}
+
+// AppendPressedKeys returns inpututil.AppendPressedKeys.
+func (EbitenInput) AppendPressedKeys(keys []ebiten.Key) []ebiten.Key {
+	return inpututil.AppendPressedKeys(keys)
+}
+
+// AppendInputChars returns ebiten.AppendInputChars.
+func (EbitenInput) AppendInputChars(runes []rune) []rune {
+	return ebiten.AppendInputChars(runes)
+}
+
+// Wheel returns ebiten.Wheel.
+func (EbitenInput) Wheel() (x, y float64) {
+	return ebiten.Wheel()
+}
+
+// AppendGamepadIDs returns ebiten.AppendGamepadIDs.
+func (EbitenInput) AppendGamepadIDs(ids []ebiten.GamepadID) []ebiten.GamepadID {
+	return ebiten.AppendGamepadIDs(ids)
+}
+
+// IsStandardGamepadButtonPressed returns ebiten.IsStandardGamepadButtonPressed.
+func (EbitenInput) IsStandardGamepadButtonPressed(id ebiten.GamepadID, b ebiten.StandardGamepadButton) bool {
+	return ebiten.IsStandardGamepadButtonPressed(id, b)
+}
+
+// StandardGamepadAxisValue returns ebiten.StandardGamepadAxisValue.
+func (EbitenInput) StandardGamepadAxisValue(id ebiten.GamepadID, axis ebiten.StandardGamepadAxis) float64 {
+	return ebiten.StandardGamepadAxisValue(id, axis)
+}
+
+// ScriptedInput is an in-memory InputSource. State is pushed into it between calls to Layout.Update, allowing input to be synthesized, replayed, or driven from tests without a window.
+type ScriptedInput struct {
+	cursorX, cursorY int
+	mouseButtons     []ebiten.MouseButton
+	touches          []scriptedTouch
+	keys             []ebiten.Key
+	runes            []rune
+	wheelX, wheelY   float64
+	gamepads         []*scriptedGamepad
+}
+
+type scriptedTouch struct {
+	id   ebiten.TouchID
+	x, y int
+}
+
+type scriptedGamepad struct {
+	id      ebiten.GamepadID
+	buttons []ebiten.StandardGamepadButton
+	axes    map[ebiten.StandardGamepadAxis]float64
+}
+
+// SetCursorPosition sets the position of the mouse cursor.
+func (s *ScriptedInput) SetCursorPosition(x, y int) {
+	s.cursorX, s.cursorY = x, y
+}
+
+// PressMouseButton marks the given mouse button as held until ReleaseMouseButton is called.
+func (s *ScriptedInput) PressMouseButton(mb ebiten.MouseButton) {
+	if !s.IsMouseButtonPressed(mb) {
+		s.mouseButtons = append(s.mouseButtons, mb)
+	}
+}
+
+// ReleaseMouseButton releases the given mouse button.
+func (s *ScriptedInput) ReleaseMouseButton(mb ebiten.MouseButton) {
+	for i, mb2 := range s.mouseButtons {
+		if mb2 == mb {
+			s.mouseButtons = append(s.mouseButtons[:i], s.mouseButtons[i+1:]...)
+			return
+		}
+	}
+}
+
+// SetTouch starts the given touch or moves it if it is already active. Note that touch IDs should start at 1, as a touch ID of 0 is treated as a mouse pointer.
+func (s *ScriptedInput) SetTouch(id ebiten.TouchID, x, y int) {
+	for i, t := range s.touches {
+		if t.id == id {
+			s.touches[i].x, s.touches[i].y = x, y
+			return
+		}
+	}
+	s.touches = append(s.touches, scriptedTouch{id: id, x: x, y: y})
+}
+
+// ReleaseTouch ends the given touch.
+func (s *ScriptedInput) ReleaseTouch(id ebiten.TouchID) {
+	for i, t := range s.touches {
+		if t.id == id {
+			s.touches = append(s.touches[:i], s.touches[i+1:]...)
+			return
+		}
+	}
+}
+
+// PressKey marks the given key as held until ReleaseKey is called.
+func (s *ScriptedInput) PressKey(k ebiten.Key) {
+	for _, k2 := range s.keys {
+		if k2 == k {
+			return
+		}
+	}
+	s.keys = append(s.keys, k)
+}
+
+// ReleaseKey releases the given key.
+func (s *ScriptedInput) ReleaseKey(k ebiten.Key) {
+	for i, k2 := range s.keys {
+		if k2 == k {
+			s.keys = append(s.keys[:i], s.keys[i+1:]...)
+			return
+		}
+	}
+}
+
+// PushInputChars queues runes to be returned by the next call to AppendInputChars.
+func (s *ScriptedInput) PushInputChars(runes ...rune) {
+	s.runes = append(s.runes, runes...)
+}
+
+// ScrollWheel queues wheel movement to be returned by the next call to Wheel.
+func (s *ScriptedInput) ScrollWheel(x, y float64) {
+	s.wheelX += x
+	s.wheelY += y
+}
+
+// gamepad returns the given gamepad, connecting it if create is true and it is not yet connected.
+func (s *ScriptedInput) gamepad(id ebiten.GamepadID, create bool) *scriptedGamepad {
+	for _, g := range s.gamepads {
+		if g.id == id {
+			return g
+		}
+	}
+	if !create {
+		return nil
+	}
+	g := &scriptedGamepad{id: id, axes: make(map[ebiten.StandardGamepadAxis]float64)}
+	s.gamepads = append(s.gamepads, g)
+	return g
+}
+
+// ConnectGamepad connects the given gamepad. Gamepads are also connected by PressGamepadButton and SetGamepadAxis.
+func (s *ScriptedInput) ConnectGamepad(id ebiten.GamepadID) {
+	s.gamepad(id, true)
+}
+
+// DisconnectGamepad disconnects the given gamepad, releasing its buttons and centering its axes.
+func (s *ScriptedInput) DisconnectGamepad(id ebiten.GamepadID) {
+	for i, g := range s.gamepads {
+		if g.id == id {
+			s.gamepads = append(s.gamepads[:i], s.gamepads[i+1:]...)
+			return
+		}
+	}
+}
+
+// PressGamepadButton marks the given button of the given gamepad as held until ReleaseGamepadButton is called.
+func (s *ScriptedInput) PressGamepadButton(id ebiten.GamepadID, b ebiten.StandardGamepadButton) {
+	if !s.IsStandardGamepadButtonPressed(id, b) {
+		g := s.gamepad(id, true)
+		g.buttons = append(g.buttons, b)
+	}
+}
+
+// ReleaseGamepadButton releases the given button of the given gamepad.
+func (s *ScriptedInput) ReleaseGamepadButton(id ebiten.GamepadID, b ebiten.StandardGamepadButton) {
+	g := s.gamepad(id, false)
+	if g == nil {
+		return
+	}
+	for i, b2 := range g.buttons {
+		if b2 == b {
+			g.buttons = append(g.buttons[:i], g.buttons[i+1:]...)
+			return
+		}
+	}
+}
+
+// SetGamepadAxis sets the value of the given axis of the given gamepad, from -1 to 1.
+func (s *ScriptedInput) SetGamepadAxis(id ebiten.GamepadID, axis ebiten.StandardGamepadAxis, value float64) {
+	s.gamepad(id, true).axes[axis] = value
+}
+
+// CursorPosition returns the position set by SetCursorPosition.
+func (s *ScriptedInput) CursorPosition() (x, y int) {
+	return s.cursorX, s.cursorY
+}
+
+// IsMouseButtonPressed returns if the given mouse button is held.
+func (s *ScriptedInput) IsMouseButtonPressed(mb ebiten.MouseButton) bool {
+	for _, mb2 := range s.mouseButtons {
+		if mb2 == mb {
+			return true
+		}
+	}
+	return false
+}
+
+// AppendTouchIDs appends the IDs of all active touches.
+func (s *ScriptedInput) AppendTouchIDs(ids []ebiten.TouchID) []ebiten.TouchID {
+	for _, t := range s.touches {
+		ids = append(ids, t.id)
+	}
+	return ids
+}
+
+// TouchPosition returns the position of the given touch, or 0, 0 if it is not active.
+func (s *ScriptedInput) TouchPosition(id ebiten.TouchID) (x, y int) {
+	for _, t := range s.touches {
+		if t.id == id {
+			return t.x, t.y
+		}
+	}
+	return 0, 0
+}
+
+// AppendPressedKeys appends all held keys.
+func (s *ScriptedInput) AppendPressedKeys(keys []ebiten.Key) []ebiten.Key {
+	return append(keys, s.keys...)
+}
+
+// AppendInputChars appends and then clears any runes queued with PushInputChars.
+func (s *ScriptedInput) AppendInputChars(runes []rune) []rune {
+	runes = append(runes, s.runes...)
+	s.runes = s.runes[:0]
+	return runes
+}
+
+// Wheel returns and then clears any wheel movement queued with ScrollWheel.
+func (s *ScriptedInput) Wheel() (x, y float64) {
+	x, y = s.wheelX, s.wheelY
+	s.wheelX, s.wheelY = 0, 0
+	return x, y
+}
+
+// AppendGamepadIDs appends the IDs of all connected gamepads.
+func (s *ScriptedInput) AppendGamepadIDs(ids []ebiten.GamepadID) []ebiten.GamepadID {
+	for _, g := range s.gamepads {
+		ids = append(ids, g.id)
+	}
+	return ids
+}
+
+// IsStandardGamepadButtonPressed returns if the given button of the given gamepad is held.
+func (s *ScriptedInput) IsStandardGamepadButtonPressed(id ebiten.GamepadID, b ebiten.StandardGamepadButton) bool {
+	if g := s.gamepad(id, false); g != nil {
+		for _, b2 := range g.buttons {
+			if b2 == b {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// StandardGamepadAxisValue returns the value of the given axis of the given gamepad, or 0 if it is not connected.
+func (s *ScriptedInput) StandardGamepadAxisValue(id ebiten.GamepadID, axis ebiten.StandardGamepadAxis) float64 {
+	if g := s.gamepad(id, false); g != nil {
+		return g.axes[axis]
+	}
+	return 0
+}
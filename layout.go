@@ -2,17 +2,17 @@ package rebui
 
 import (
 	"encoding/json"
+	"fmt"
 	"image/color"
-	"log"
 	"math"
 	"reflect"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/inpututil"
 	"github.com/hajimehoshi/ebiten/v2/text/v2"
 	"github.com/kettek/rebui/events"
 	"github.com/kettek/rebui/style"
@@ -26,12 +26,37 @@ import (
 type Layout struct {
 	RenderTarget  *ebiten.Image
 	ClampPointers bool
-	generated     bool
-	Nodes         Nodes
-	currentState  currentState
+	Input         InputSource // Input is polled for pointer, touch, and key state during Update. If nil, EbitenInput is used.
+	CollectErrors bool        // If true, errors from Generate and Layout are collected and available from Errors rather than being logged.
+	// If true, the arrow keys move focus to the nearest focusable node in their direction, unless the focused node prevents the key press's default action.
+	ArrowNavigation bool
+	// If set, gamepad input moves focus and activates or cancels the focused node as mapped. Gamepad events are sent to the focused node regardless.
+	Gamepad *GamepadMapping
+	// How long a pointer must be held without moving to send a PointerLongPress event. If 0, 500ms is used.
+	LongPressDuration time.Duration
+	// The most time between taps for them to count as successive taps, such as a double-click. If 0, 400ms is used.
+	MultiTapInterval time.Duration
+	// How far a pressed pointer may move and still tap or long press, and how far apart successive taps may be. If 0, 8 is used.
+	TapSlop float64
+	// Clock returns the current time, which is used to timestamp events and to time gestures and key repeats. If nil, time.Now is used.
+	Clock        func() time.Time
+	generated    bool
+	Nodes        Nodes
+	currentState currentState
 	//
 	noRelayout          bool // If the layout should not redo its layout. This is a negatively named field so the '0' value means we should relayout.
 	pressedKeys         []key
+	modifiers           events.Modifiers // The modifier keys held as of the last events collected.
+	shortcuts           []*Shortcut
+	gestures            map[events.PointerID]*pointerGesture
+	lastTap             pointerTap
+	touchGesture        *touchGesture
+	drag                *dragSession
+	captures            map[events.PointerID]*Node // Nodes that have captured pointers, keyed by pointer ID.
+	cursor              Cursor                     // The cursor last applied. This is empty until a cursor is first applied.
+	gamepadButtons      []gamepadButton
+	gamepadAxes         map[gamepadAxis]float64
+	gamepadSticks       map[ebiten.GamepadID]gamepadStick
 	pressedMouseButtons []mouse
 	activeTouches       []touch
 	focusedNode         *Node
@@ -39,7 +64,12 @@ type Layout struct {
 	lastMouseY          int
 	lastWidth           float64
 	lastHeight          float64
+	viewportWidth       float64 // The outer width passed to Layout, used for vw units.
+	viewportHeight      float64 // The outer height passed to Layout, used for vh units.
 	parser              *tokenizer.Tokenizer
+	generateErrors      []error
+	layoutErrors        []error
+	cells               map[*Node][]LayoutContext // The cells of each CellContainerWidget, determined during layout.
 }
 
 type key struct {
@@ -100,21 +130,39 @@ func (l *Layout) Generate() {
 	l.noRelayout = false
 }
 
-// Layout repositions all nodes.
+// Layout repositions all nodes. The layout is considered current until nodes are added or removed or the render target changes size.
 func (l *Layout) Layout(ctx LayoutContext) {
+	l.layoutErrors = nil
+	l.viewportWidth = ctx.OuterWidth
+	l.viewportHeight = ctx.OuterHeight
 	l.layoutNodes(l.Nodes, ctx)
+	l.layoutScrollContent()
+	l.noRelayout = true
 }
 
+// layoutNodes lays out the given nodes and their children in dependency order, so that any node referenced by another is resolved first regardless of declaration order.
 func (l *Layout) layoutNodes(ns Nodes, ctx LayoutContext) {
-	for _, n := range ns {
-		l.layoutNode(n, ctx)
-		// Now iterate the children.
-		l.layoutNodes(n.Children, LayoutContext{
-			OuterX:      n.x,
-			OuterY:      n.y,
-			OuterWidth:  n.width,
-			OuterHeight: n.height,
-		})
+	l.cells = nil
+	for _, step := range l.resolveOrder(ns) {
+		nodeCtx := ctx
+		if p := step.parent; p != nil {
+			nodeCtx = p.contentContext()
+			if step.kind == stepSize {
+				if cellCtx, ok := l.cellContext(p, step.node); ok {
+					nodeCtx = cellCtx
+				}
+			}
+		}
+		switch step.kind {
+		case stepSize:
+			l.layoutNodeSize(step.node, nodeCtx)
+		case stepX:
+			l.layoutNodeX(step.node, nodeCtx)
+		case stepY:
+			l.layoutNodeY(step.node, nodeCtx)
+		case stepArrange:
+			l.arrangeNode(step.node)
+		}
 	}
 }
 
@@ -132,26 +180,60 @@ func (l *Layout) AddNode(n Node) *Node {
 	return l.Nodes[len(l.Nodes)-1]
 }
 
-// RemoveNode removes the given node from the layout.
+// RemoveNode removes the given node from the layout. If the node or one of its children is focused, it is unfocused first, and any pointer captures, gestures, or drag involving them are ended.
 func (l *Layout) RemoveNode(n *Node) {
 	// TODO: Add/Use children aware Nodes func
 	for i, node := range l.Nodes {
 		if node == n {
+			if n.isAncestorOf(l.focusedNode) {
+				l.Blur()
+			}
+			l.releaseNode(n)
 			l.Nodes = append(l.Nodes[:i], l.Nodes[i+1:]...)
+			l.shortcuts = slices.DeleteFunc(l.shortcuts, func(s *Shortcut) bool {
+				return s.Node != nil && n.isAncestorOf(s.Node)
+			})
 			l.noRelayout = false
 			return
 		}
 	}
 }
 
+// releaseNode ends the drag, pointer captures, and gestures that involve the node or its children, such as when it is removed.
+func (l *Layout) releaseNode(n *Node) {
+	if d := l.drag; d != nil && (n.isAncestorOf(d.source) || n.isAncestorOf(d.over)) {
+		l.CancelDrag()
+	}
+	for pid, captor := range l.captures {
+		if n.isAncestorOf(captor) {
+			l.ReleasePointerCapture(pid)
+		}
+	}
+	for pid, g := range l.gestures {
+		if n.isAncestorOf(g.node) {
+			delete(l.gestures, pid)
+		}
+	}
+	if g := l.touchGesture; g != nil && n.isAncestorOf(g.node) {
+		l.touchGesture = nil
+	}
+}
+
+func (l *Layout) input() InputSource {
+	if l.Input != nil {
+		return l.Input
+	}
+	return EbitenInput{}
+}
+
 func (l *Layout) getCursor() (x, y int) {
 	if l.RenderTarget != nil {
 		w, h := l.RenderTarget.Bounds().Dx(), l.RenderTarget.Bounds().Dy()
-		x, y = ebiten.CursorPosition()
+		x, y = l.input().CursorPosition()
 		x = int((float64(x) / float64(w)) * float64(w))
 		y = int((float64(y) / float64(h)) * float64(h))
 	} else {
-		x, y = ebiten.CursorPosition()
+		x, y = l.input().CursorPosition()
 	}
 	return
 }
@@ -165,9 +247,28 @@ func (l *Layout) getSize() (w, h int) {
 }
 
 func (l *Layout) getEvents() (evts []Event) {
+	l.modifiers = l.getModifiers()
 	evts = append(evts, l.getMouseEvents()...)
 	evts = append(evts, l.getTouchEvents()...)
 	evts = append(evts, l.getKeyEvents()...)
+	evts = append(evts, l.getGamepadEvents()...)
+	return
+}
+
+// getModifiers returns the modifier keys currently held.
+func (l *Layout) getModifiers() (m events.Modifiers) {
+	for _, k := range l.input().AppendPressedKeys(nil) {
+		switch k {
+		case ebiten.KeyShift, ebiten.KeyShiftLeft, ebiten.KeyShiftRight:
+			m.Shift = true
+		case ebiten.KeyControl, ebiten.KeyControlLeft, ebiten.KeyControlRight:
+			m.Ctrl = true
+		case ebiten.KeyAlt, ebiten.KeyAltLeft, ebiten.KeyAltRight:
+			m.Alt = true
+		case ebiten.KeyMeta, ebiten.KeyMetaLeft, ebiten.KeyMetaRight:
+			m.Meta = true
+		}
+	}
 	return
 }
 
@@ -180,7 +281,7 @@ func (l *Layout) getMouseEvents() (evts []Event) {
 	}
 	deltaX, deltaY := x-l.lastMouseX, y-l.lastMouseY
 	l.lastMouseX, l.lastMouseY = x, y
-	ts := time.Now()
+	ts := l.now()
 
 	var pressedMouseButtons []mouse
 	var newPressedMouseButtons []mouse
@@ -194,7 +295,7 @@ func (l *Layout) getMouseEvents() (evts []Event) {
 
 	// Get current press state.
 	for _, mb := range checkMouseButtons {
-		if ebiten.IsMouseButtonPressed(mb) {
+		if l.input().IsMouseButtonPressed(mb) {
 			pressedMouseButtons = append(pressedMouseButtons, mouse{id: mb, time: ts})
 		}
 	}
@@ -237,11 +338,12 @@ func (l *Layout) getMouseEvents() (evts []Event) {
 		evts = append(evts, &events.PointerPress{
 			Timestamp: events.Timestamp{Timestamp: ts},
 			Pointer: events.Pointer{
-				X:        float64(x),
-				Y:        float64(y),
-				DX:       float64(deltaX),
-				DY:       float64(deltaY),
-				ButtonID: int(mb.id),
+				X:         float64(x),
+				Y:         float64(y),
+				DX:        float64(deltaX),
+				DY:        float64(deltaY),
+				ButtonID:  int(mb.id),
+				Modifiers: l.modifiers,
 			},
 		})
 	}
@@ -251,11 +353,12 @@ func (l *Layout) getMouseEvents() (evts []Event) {
 			Timestamp: events.Timestamp{Timestamp: ts},
 			Duration:  events.Duration{Duration: ts.Sub(mb.time)},
 			Pointer: events.Pointer{
-				X:        float64(x),
-				Y:        float64(y),
-				DX:       float64(deltaX),
-				DY:       float64(deltaY),
-				ButtonID: int(mb.id),
+				X:         float64(x),
+				Y:         float64(y),
+				DX:        float64(deltaX),
+				DY:        float64(deltaY),
+				ButtonID:  int(mb.id),
+				Modifiers: l.modifiers,
 			},
 		})
 	}
@@ -266,11 +369,12 @@ func (l *Layout) getMouseEvents() (evts []Event) {
 				Timestamp: events.Timestamp{Timestamp: ts},
 				Duration:  events.Duration{Duration: ts.Sub(mb.time)},
 				Pointer: events.Pointer{
-					X:        float64(x),
-					Y:        float64(y),
-					DX:       float64(deltaX),
-					DY:       float64(deltaY),
-					ButtonID: int(mb.id),
+					X:         float64(x),
+					Y:         float64(y),
+					DX:        float64(deltaX),
+					DY:        float64(deltaY),
+					ButtonID:  int(mb.id),
+					Modifiers: l.modifiers,
 				},
 			})
 		}
@@ -278,11 +382,26 @@ func (l *Layout) getMouseEvents() (evts []Event) {
 		evts = append(evts, &events.PointerMove{
 			Timestamp: events.Timestamp{Timestamp: ts},
 			Pointer: events.Pointer{
-				X:        float64(x),
-				Y:        float64(y),
-				DX:       float64(deltaX),
-				DY:       float64(deltaY),
-				ButtonID: -1,
+				X:         float64(x),
+				Y:         float64(y),
+				DX:        float64(deltaX),
+				DY:        float64(deltaY),
+				ButtonID:  -1,
+				Modifiers: l.modifiers,
+			},
+		})
+	}
+
+	if wheelX, wheelY := l.input().Wheel(); wheelX != 0 || wheelY != 0 {
+		evts = append(evts, &events.PointerWheel{
+			Timestamp: events.Timestamp{Timestamp: ts},
+			Pointer: events.Pointer{
+				X:         float64(x),
+				Y:         float64(y),
+				DX:        wheelX,
+				DY:        wheelY,
+				ButtonID:  -1,
+				Modifiers: l.modifiers,
 			},
 		})
 	}
@@ -299,11 +418,12 @@ func (l *Layout) getTouchEvents() (evts []Event) {
 	var newTouches []touch
 	var oldTouches []touch
 
-	ts := time.Now()
+	ts := l.now()
 
 	// Get current touch state.
-	for _, id := range ebiten.AppendTouchIDs(nil) {
-		tx, ty := ebiten.TouchPosition(id)
+	input := l.input()
+	for _, id := range input.AppendTouchIDs(nil) {
+		tx, ty := input.TouchPosition(id)
 		activeTouches = append(activeTouches, touch{id: id, x: tx, y: ty, time: ts})
 	}
 
@@ -340,7 +460,7 @@ func (l *Layout) getTouchEvents() (evts []Event) {
 		if !exists {
 			newTouches = append(newTouches, t)
 		} else {
-			tx, ty := ebiten.TouchPosition(t.id)
+			tx, ty := input.TouchPosition(t.id)
 			prevTouch.deltaX = tx - prevTouch.x
 			prevTouch.deltaY = ty - prevTouch.y
 			prevTouch.movement += int(math.Abs(float64(prevTouch.deltaX)) + math.Abs(float64(prevTouch.deltaY)))
@@ -356,11 +476,12 @@ func (l *Layout) getTouchEvents() (evts []Event) {
 		evts = append(evts, &events.PointerPress{
 			Timestamp: events.Timestamp{Timestamp: ts},
 			Pointer: events.Pointer{
-				X:       float64(t.x),
-				Y:       float64(t.y),
-				DX:      float64(t.deltaX),
-				DY:      float64(t.deltaY),
-				TouchID: int(t.id),
+				X:         float64(t.x),
+				Y:         float64(t.y),
+				DX:        float64(t.deltaX),
+				DY:        float64(t.deltaY),
+				TouchID:   int(t.id),
+				Modifiers: l.modifiers,
 			},
 		})
 	}
@@ -370,11 +491,12 @@ func (l *Layout) getTouchEvents() (evts []Event) {
 			Timestamp: events.Timestamp{Timestamp: ts},
 			Duration:  events.Duration{Duration: ts.Sub(t.time)},
 			Pointer: events.Pointer{
-				X:       float64(t.x),
-				Y:       float64(t.y),
-				DX:      float64(t.deltaX),
-				DY:      float64(t.deltaY),
-				TouchID: int(t.id),
+				X:         float64(t.x),
+				Y:         float64(t.y),
+				DX:        float64(t.deltaX),
+				DY:        float64(t.deltaY),
+				TouchID:   int(t.id),
+				Modifiers: l.modifiers,
 			},
 		})
 	}
@@ -385,11 +507,12 @@ func (l *Layout) getTouchEvents() (evts []Event) {
 				Timestamp: events.Timestamp{Timestamp: ts},
 				Duration:  events.Duration{Duration: ts.Sub(t.time)},
 				Pointer: events.Pointer{
-					X:       float64(t.x),
-					Y:       float64(t.y),
-					DX:      float64(t.deltaX),
-					DY:      float64(t.deltaY),
-					TouchID: int(t.id),
+					X:         float64(t.x),
+					Y:         float64(t.y),
+					DX:        float64(t.deltaX),
+					DY:        float64(t.deltaY),
+					TouchID:   int(t.id),
+					Modifiers: l.modifiers,
 				},
 			})
 		}
@@ -401,14 +524,14 @@ func (l *Layout) getTouchEvents() (evts []Event) {
 }
 
 func (l *Layout) getKeyEvents() (evts []Event) {
-	ts := time.Now()
+	ts := l.now()
 
 	var pressedKeys []key
 	var releasedKeys []key
 	var newPressedKeys []key
 	var repeatKeys []key
 
-	for _, k := range inpututil.AppendPressedKeys(nil) {
+	for _, k := range l.input().AppendPressedKeys(nil) {
 		pressedKeys = append(pressedKeys, key{key: k, time: ts, next: ts.Add(500 * time.Millisecond)})
 	}
 
@@ -452,12 +575,14 @@ func (l *Layout) getKeyEvents() (evts []Event) {
 	for _, k := range newPressedKeys {
 		evts = append(evts, &events.KeyPress{
 			Timestamp: events.Timestamp{Timestamp: ts},
+			Modifiers: l.modifiers,
 			Key:       k.key,
 		})
 	}
 	for _, k := range releasedKeys {
 		evts = append(evts, &events.KeyRelease{
 			Timestamp: events.Timestamp{Timestamp: ts},
+			Modifiers: l.modifiers,
 			Key:       k.key,
 			Duration:  events.Duration{Duration: ts.Sub(k.time)},
 		})
@@ -466,15 +591,17 @@ func (l *Layout) getKeyEvents() (evts []Event) {
 	for _, k := range repeatKeys {
 		evts = append(evts, &events.KeyPress{
 			Timestamp: events.Timestamp{Timestamp: ts},
+			Modifiers: l.modifiers,
 			Key:       k.key,
 			Repeat:    k.count,
 		})
 	}
 
 	// Also handle input chars. AFAIK we shouldn't handle the whole key press logic with input chars.
-	for _, k := range ebiten.AppendInputChars(nil) {
+	for _, k := range l.input().AppendInputChars(nil) {
 		evts = append(evts, &events.KeyInput{
 			Timestamp: events.Timestamp{Timestamp: ts},
+			Modifiers: l.modifiers,
 			Rune:      k,
 		})
 	}
@@ -489,23 +616,26 @@ func (l *Layout) Update() {
 	if !l.noRelayout {
 		w, h := l.getSize()
 		l.Layout(LayoutContext{0, 0, float64(w), float64(h)})
-		l.noRelayout = true
 	}
 
 	// TODO: Allow passing in a block evts list, where various event types can be prevented from occurring -- this might come in use.
 	if evts := l.getEvents(); len(evts) > 0 {
 		for _, e := range evts {
-			// Iterate our nodes...
-			l.Nodes.ForEachDeepest(func(n *Node) bool {
-				l.processNodeEvent(n, e)
-				if ec, ok := e.(EventCancelable); ok && ec.Canceled() {
-					return true
-				}
-				return false
-			})
 			l.processEvent(e)
 		}
 	}
+	l.processLongPresses()
+	l.processTouchGesture()
+	l.processGamepadStick()
+
+	l.Nodes.ForEach(func(n *Node) bool {
+		if u, ok := n.Widget.(receivers.Update); ok {
+			u.HandleUpdate()
+		}
+		return false
+	})
+
+	l.updateCursor()
 }
 
 // Draw draws the Nodes to the screen
@@ -521,44 +651,51 @@ func (l *Layout) Draw(screen *ebiten.Image) {
 	if !l.noRelayout {
 		w, h := l.getSize()
 		l.Layout(LayoutContext{0, 0, float64(w), float64(h)})
-		l.noRelayout = true
 	}
 
 	l.Nodes.ForEach(func(n *Node) bool {
-		op := &ebiten.DrawImageOptions{}
 		if n.Widget != nil {
-			if xg, ok := n.Widget.(getters.X); ok {
-				op.GeoM.Translate(xg.GetX(), 0)
-			} else {
-				op.GeoM.Translate(n.x, 0)
+			if target, op, ok := l.drawTarget(n); ok {
+				n.Widget.Draw(target, op)
 			}
-			if yg, ok := n.Widget.(getters.Y); ok {
-				op.GeoM.Translate(0, yg.GetY())
-			} else {
-				op.GeoM.Translate(0, n.y)
+		}
+		return false
+	})
+
+	l.Nodes.ForEach(func(n *Node) bool {
+		if ow, ok := n.Widget.(OverlayWidget); ok {
+			if target, op, ok := l.drawTarget(n); ok {
+				ow.DrawOverlay(target, op)
 			}
-			n.Widget.Draw(l.RenderTarget, op)
 		}
 		return false
 	})
+
+	l.drawDrag()
 }
 
 // HasEvents returns if there are any active events like a mouse press,
 func (l *Layout) HasEvents() bool {
-	if len(l.currentState.hoveredNodes) > 0 || len(l.currentState.pressedNodes) > 0 || len(l.pressedKeys) > 0 || len(l.activeTouches) > 0 || len(l.pressedMouseButtons) > 0 {
+	if len(l.currentState.hoveredNodes) > 0 || len(l.currentState.pressedNodes) > 0 || len(l.pressedKeys) > 0 || len(l.gamepadButtons) > 0 || len(l.activeTouches) > 0 || len(l.pressedMouseButtons) > 0 {
 		return true
 	}
 	return false
 }
 
-// ClearEvents clears all events that have been processed, such as pointer presses, key presses, etc.
+// ClearEvents clears all events that have been processed, such as pointer presses, key presses, etc. Any drag in progress is canceled, as with CancelDrag.
 func (l *Layout) ClearEvents() {
 	l.currentState.hoveredNodes = nil
 	l.currentState.pressedNodes = nil
 	l.pressedKeys = nil
+	l.gamepadButtons = nil
 	l.activeTouches = nil
 	l.pressedMouseButtons = nil
 	l.focusedNode = nil
+	l.gestures = nil
+	l.lastTap = pointerTap{}
+	l.touchGesture = nil
+	l.CancelDrag()
+	l.captures = nil
 }
 
 func (l *Layout) generateNode(n *Node) {
@@ -566,6 +703,9 @@ func (l *Layout) generateNode(n *Node) {
 	if n.Widget != nil {
 		return
 	}
+	if _, ok := handlers[n.Type]; !ok && n.Type != "" {
+		l.reportError(&l.generateErrors, newNodeError(n, "Type", fmt.Errorf("%w %q", ErrUnknownNodeType, n.Type)))
+	}
 	for k, h := range handlers {
 		if k == n.Type {
 			n.Widget = reflect.New(reflect.TypeOf(h).Elem()).Interface().(Widget)
@@ -607,27 +747,20 @@ func (l *Layout) generateNode(n *Node) {
 					if err == nil {
 						ff.AssignFontFace(face)
 					} else {
-						log.Println(err)
-					}
-				}
-			}
-			if n.FontSize != "" {
-				if fs, ok := n.Widget.(assigners.FontSize); ok {
-					if ff, ok := style.CurrentTheme().FontFace.(*text.GoTextFace); ok {
-						size, _ := stringToPosition(l, n.FontSize, ff.Size, true) // FIXME: This re-use is goofy, as it allows unintended at/after usage.
-						fs.AssignFontSize(size)
+						l.reportError(&l.generateErrors, newNodeError(n, "Font", fmt.Errorf("%w: %w", ErrLoaderFailure, err)))
 					}
 				}
 			}
+			l.layoutFontSize(n)
 			if is, ok := n.Widget.(assigners.ImageStretch); ok {
 				is.AssignImageStretch(n.ImageStretch)
 			}
-			if is, ok := n.Widget.(assigners.Image); ok {
+			if is, ok := n.Widget.(assigners.Image); ok && n.Image != "" {
 				img, err := LoadImage(n.Image)
 				if err == nil {
 					is.AssignImage(img)
 				} else {
-					log.Println(err)
+					l.reportError(&l.generateErrors, newNodeError(n, "Image", fmt.Errorf("%w: %w", ErrLoaderFailure, err)))
 				}
 			}
 			if ds, ok := n.Widget.(assigners.Disable); ok {
@@ -637,7 +770,7 @@ func (l *Layout) generateNode(n *Node) {
 			if _, ok := n.Widget.(getters.Template); ok {
 				template, err := LoadTemplate(n.Source)
 				if err != nil {
-					log.Println(err)
+					l.reportError(&l.generateErrors, newNodeError(n, "Source", fmt.Errorf("%w: %w", ErrLoaderFailure, err)))
 					continue
 				}
 				for _, n2 := range template {
@@ -658,25 +791,22 @@ func (l *Layout) generateNode(n *Node) {
 	if gh, ok := n.Widget.(receivers.Generate); ok {
 		gh.HandleGenerate()
 	}
+	if n.Widget != nil {
+		l.bindNodeShortcut(n)
+	}
 }
 
+// templateRelationRegexp matches any relation within a position string along with its target ID.
+var templateRelationRegexp = regexp.MustCompile(`\b(of|at|after|before)[\t\s]+([^\t\s,()+\-*/]+)`)
+
 // fixTemplateNodeIDs prepends the passed parentID to the node's ID. As part of this, it also checks for and makes any relative position calls (e.g., "after neighbor", "50% of neighbor", etc.) to also have the parentID prepended to those calls. e.g., "at sibling" -> "at parentID__sibling"
 func (l *Layout) fixTemplateNodeIDs(parentID string, n *Node) {
 	id := parentID + "__" + n.ID
 	for _, n2 := range n.Children {
 		l.fixTemplateNodeIDs(id, n2)
 	}
-	r := regexp.MustCompile("(of|at|after|before)[\\t\\s](.[^\\t\\s])")
-
 	replaceID := func(orig, prepend string) string {
-		if orig == "" {
-			return ""
-		}
-		result := r.FindStringSubmatchIndex(orig)
-		if len(result) < 6 {
-			return orig
-		}
-		return orig[:result[0]] + orig[result[0]:result[3]] + " " + prepend + "__" + orig[result[4]:result[5]] + orig[result[5]:]
+		return templateRelationRegexp.ReplaceAllString(orig, "${1} "+prepend+"__${2}")
 	}
 
 	n.X = replaceID(n.X, parentID)
@@ -689,12 +819,24 @@ func (l *Layout) fixTemplateNodeIDs(parentID string, n *Node) {
 	n.ID = id
 }
 
-// layoutNode sets the node's various positions and sizings based upon the containing outer width and height.
-func (l *Layout) layoutNode(n *Node, ctx LayoutContext) {
+// layoutFontSize assigns the node's font size. This is done during both generation and layout, as the size may depend upon the viewport or other nodes.
+func (l *Layout) layoutFontSize(n *Node) {
+	if n.FontSize != "" {
+		if fs, ok := n.Widget.(assigners.FontSize); ok {
+			if ff, ok := style.CurrentTheme().FontFace.(*text.GoTextFace); ok {
+				size, _ := l.nodePosition(n, "FontSize", n.FontSize, ff.Size, 0, true) // FIXME: This re-use is goofy, as it allows unintended at/after usage.
+				fs.AssignFontSize(size)
+			}
+		}
+	}
+}
+
+// layoutNodeSize sets the node's sizing based upon the containing outer width and height.
+func (l *Layout) layoutNodeSize(n *Node, ctx LayoutContext) {
 	nodeWidth := ctx.OuterWidth
 	nodeHeight := ctx.OuterHeight
-	nodeX := 0.0
-	nodeY := 0.0
+
+	l.layoutFontSize(n)
 
 	var skipWidth bool
 	var skipHeight bool
@@ -710,273 +852,229 @@ func (l *Layout) layoutNode(n *Node, ctx LayoutContext) {
 	}
 
 	if !skipWidth && n.Width != "" {
-		nodeWidth, _ = stringToPosition(l, n.Width, ctx.OuterWidth, false)
+		nodeWidth, _ = l.nodePosition(n, "Width", n.Width, ctx.OuterWidth, 0, false)
 	}
 	if !skipHeight && n.Height != "" {
-		nodeHeight, _ = stringToPosition(l, n.Height, ctx.OuterHeight, true)
+		nodeHeight, _ = l.nodePosition(n, "Height", n.Height, ctx.OuterHeight, 0, true)
 	}
 
+	l.assignNodeSize(n, nodeWidth, nodeHeight)
+}
+
+// assignNodeSize allows the node's widget to adjust the given size, then assigns it to both the widget and the node. The node's padding is also resolved, as it may depend upon the size.
+func (l *Layout) assignNodeSize(n *Node, width, height float64) {
 	// Allow the widget to layout its final size.
 	if lw, ok := n.Widget.(LayoutWidget); ok {
-		nodeWidth, nodeHeight = lw.Layout(nodeWidth, nodeHeight)
+		width, height = lw.Layout(width, height)
 	}
 	// And then assign it.
 	if wa, ok := n.Widget.(assigners.Width); ok {
-		wa.AssignWidth(nodeWidth)
+		wa.AssignWidth(width)
 	}
 	if ha, ok := n.Widget.(assigners.Height); ok {
-		ha.AssignHeight(nodeHeight)
+		ha.AssignHeight(height)
 	}
 
-	n.width = nodeWidth
-	n.height = nodeHeight
+	n.width = width
+	n.height = height
 
+	n.paddingX, _ = l.nodePosition(n, "Padding", n.Padding, width, 0, false)
+	n.paddingY, _ = l.nodePosition(n, "Padding", n.Padding, height, 0, true)
+}
+
+// layoutNodeX sets the node's x position based upon the containing outer x and width. This must be called after layoutNodeSize.
+func (l *Layout) layoutNodeX(n *Node, ctx LayoutContext) {
 	// Check if X has changed by comparing any user-set value to our stored node value.
-	var skipX bool
-	var skipY bool
 	if xg, ok := n.Widget.(getters.X); ok {
 		if xg.GetX() != n.x {
-			skipX = true
+			return
 		}
 	}
+
+	var nodeX float64
+	// Origin uses the node's own width and height to determine offsets.
+	originX, _ := l.nodePosition(n, "OriginX", n.OriginX, n.width, n.width, false)
+	if oxs, ok := n.Widget.(assigners.OriginX); ok {
+		oxs.AssignOriginX(originX)
+	}
+	if n.X != "" {
+		nodeX, n.isRelativeX = l.nodePosition(n, "X", n.X, ctx.OuterWidth, n.width, false)
+	} else {
+		nodeX, n.isRelativeX = 0, false
+	}
+	// Only add outer x if we're not relative (e.g., at/after/before/of)
+	if !n.isRelativeX {
+		n.x = ctx.OuterX
+	} else {
+		n.x = 0
+	}
+	n.x += nodeX + originX
+
+	if xs, ok := n.Widget.(assigners.X); ok {
+		xs.AssignX(n.x)
+	}
+}
+
+// layoutNodeY sets the node's y position based upon the containing outer y and height. This must be called after layoutNodeSize.
+func (l *Layout) layoutNodeY(n *Node, ctx LayoutContext) {
 	if yg, ok := n.Widget.(getters.Y); ok {
 		if yg.GetY() != n.y {
-			skipY = true
+			return
 		}
 	}
 
-	if !skipX {
-		// Origin uses the node's own width and height to determine offsets.
-		originX, _ := stringToPosition(l, n.OriginX, nodeWidth, false)
-		if oxs, ok := n.Widget.(assigners.OriginX); ok {
-			oxs.AssignOriginX(originX)
-		}
-		if n.X != "" {
-			nodeX, n.isRelativeX = stringToPosition(l, n.X, ctx.OuterWidth, false)
-		} else {
-			nodeX, n.isRelativeX = 0, false
-		}
-		// Only add outer x if we're not relative (e.g., at/after/before/of)
-		if !n.isRelativeX {
-			n.x = ctx.OuterX
-		} else {
-			n.x = 0
-		}
-		n.x += nodeX + originX
-
-		if xs, ok := n.Widget.(assigners.X); ok {
-			xs.AssignX(n.x)
-		}
+	var nodeY float64
+	originY, _ := l.nodePosition(n, "OriginY", n.OriginY, n.height, n.height, true)
+	if oys, ok := n.Widget.(assigners.OriginY); ok {
+		oys.AssignOriginY(originY)
 	}
-	if !skipY {
-		originY, _ := stringToPosition(l, n.OriginY, nodeHeight, true)
-		if oys, ok := n.Widget.(assigners.OriginY); ok {
-			oys.AssignOriginY(originY)
-		}
-		if n.Y != "" {
-			nodeY, n.isRelativeY = stringToPosition(l, n.Y, ctx.OuterHeight, true)
-		} else {
-			nodeY, n.isRelativeY = 0, false
-		}
-		if !n.isRelativeY {
-			n.y = ctx.OuterY
-		} else {
-			n.y = 0
-		}
-		n.y += nodeY + originY
+	if n.Y != "" {
+		nodeY, n.isRelativeY = l.nodePosition(n, "Y", n.Y, ctx.OuterHeight, n.height, true)
+	} else {
+		nodeY, n.isRelativeY = 0, false
+	}
+	if !n.isRelativeY {
+		n.y = ctx.OuterY
+	} else {
+		n.y = 0
+	}
+	n.y += nodeY + originY
 
-		if ys, ok := n.Widget.(assigners.Y); ok {
-			ys.AssignY(n.y)
-		}
+	if ys, ok := n.Widget.(assigners.Y); ok {
+		ys.AssignY(n.y)
 	}
 }
 
-func (l *Layout) processNodeEvent(n *Node, e Event) {
-	if hit, ok := n.Widget.(HitChecker); ok {
-		switch evt := e.(type) {
-		case *events.PointerMove:
-			if hit.Hit(evt.X, evt.Y) {
-				evt.Widget = n.Widget
-				if gx, ok := n.Widget.(getters.X); ok {
-					evt.RelativeX = evt.X - gx.GetX()
-				}
-				if gy, ok := n.Widget.(getters.Y); ok {
-					evt.RelativeY = evt.Y - gy.GetY()
-				}
+// processEvent dispatches the event to the nodes it concerns and then performs its default action, unless prevented. Pointer events are dispatched to the topmost node under the pointer, while key and gamepad events are dispatched to the focused node.
+func (l *Layout) processEvent(e Event) {
+	switch evt := e.(type) {
+	case *events.PointerMove:
+		// Hovering always follows the pointer, even while it is captured.
+		l.updateHover(l.hitTarget(evt.X, evt.Y), evt)
+		if target := l.pointerTarget(evt.Pointer); target != nil {
+			l.dispatchEvent(target, evt, func(n *Node) {
 				if n.OnPointerMove != nil {
 					n.OnPointerMove(evt)
 				}
+				if evt.Canceled() {
+					return
+				}
 				if hmove, ok := n.Widget.(receivers.PointerMove); ok {
 					hmove.HandlePointerMove(evt)
 				}
-				if !l.currentState.isHovered(n) {
-					pointerInEvent := &events.PointerIn{
-						TargetWidget: events.TargetWidget{Widget: n.Widget},
-						Timestamp:    evt.Timestamp,
-						Pointer:      evt.Pointer,
-					}
-					if n.OnPointerIn != nil {
-						n.OnPointerIn(pointerInEvent)
-					}
-					if hin, ok := n.Widget.(receivers.PointerIn); ok {
-						hin.HandlePointerIn(pointerInEvent)
-					}
-					l.currentState.addHovered(n)
+			})
+		}
+		l.moveGesture(evt)
+		pid := evt.ID()
+		// Handle any global move handlers that were pressed.
+		l.Nodes.ForEach(func(n *Node) bool {
+			if l.currentState.isPressed(n, pid) {
+				retarget(evt, n, n, events.PhaseNone)
+				if n.OnPointerGlobalMove != nil {
+					n.OnPointerGlobalMove(evt)
 				}
-			} else {
-				if l.currentState.isHovered(n) {
-					pointerOutEvent := &events.PointerOut{
-						TargetWidget: events.TargetWidget{Widget: n.Widget},
-						Timestamp:    evt.Timestamp,
-						Pointer:      evt.Pointer,
-					}
-					if n.OnPointerOut != nil {
-						n.OnPointerOut(pointerOutEvent)
-					}
-					if hout, ok := n.Widget.(receivers.PointerOut); ok {
-						hout.HandlePointerOut(pointerOutEvent)
-					}
-					l.currentState.removeHovered(n)
+				if hmove, ok := n.Widget.(receivers.PointerGlobalMove); ok {
+					hmove.HandlePointerGlobalMove(evt)
 				}
 			}
-		case *events.PointerPress:
-			if hit.Hit(evt.X, evt.Y) {
-				pid := -1
-				if evt.TouchID > 0 { // I hope touches can't be 0...
-					pid = evt.TouchID
-				} else {
-					pid = evt.ButtonID
-				}
-				evt.Widget = n.Widget
-				if gx, ok := n.Widget.(getters.X); ok {
-					evt.RelativeX = evt.X - gx.GetX()
-				}
-				if gy, ok := n.Widget.(getters.Y); ok {
-					evt.RelativeY = evt.Y - gy.GetY()
+			return false
+		})
+	case *events.PointerPress:
+		target := l.pointerTarget(evt.Pointer)
+		if target != nil {
+			pid := evt.ID()
+			var captor *Node
+			l.dispatchEvent(target, evt, func(n *Node) {
+				if !l.currentState.isPressed(n, pid) {
+					l.currentState.addPressed(n, pid)
 				}
 				if n.OnPointerPress != nil {
 					n.OnPointerPress(evt)
 				}
+				if evt.Canceled() {
+					return
+				}
 				if hpress, ok := n.Widget.(receivers.PointerPress); ok {
 					hpress.HandlePointerPress(evt)
 				}
-				if !l.currentState.isPressed(n, pid) {
-					l.currentState.addPressed(n, pid)
-				}
-				// Focus node on pointer press -- do we want to limit focused to only first or last node receiving a pointer press?
-				if l.focusedNode != nil && l.focusedNode != n {
-					unfocusEvent := &events.Unfocus{
-						TargetWidget: events.TargetWidget{Widget: l.focusedNode.Widget},
-						Timestamp:    evt.Timestamp,
-					}
-					if l.focusedNode.OnUnfocus != nil {
-						l.focusedNode.OnUnfocus(unfocusEvent)
-					}
-					if hunfocus, ok := l.focusedNode.Widget.(receivers.Unfocus); ok {
-						hunfocus.HandleUnfocus(unfocusEvent)
-					}
-				}
-				if n.FocusIndex > 0 {
-					if l.focusedNode != n {
-						focusEvent := &events.Focus{
-							TargetWidget: events.TargetWidget{Widget: evt.Widget},
-							Timestamp:    evt.Timestamp,
-							Pointer:      evt.Pointer,
-						}
-						if n.OnFocus != nil {
-							n.OnFocus(focusEvent)
-						}
-						if hfocus, ok := n.Widget.(receivers.Focus); ok {
-							hfocus.HandleFocus(focusEvent)
-						}
-						l.focusedNode = n
-					}
-				} else {
-					l.focusedNode = nil
+				if captor == nil && evt.CaptureRequested() {
+					captor = n
 				}
+			})
+			if captor != nil {
+				l.CapturePointer(captor, pid)
 			}
-		case *events.PointerRelease:
-			if hit.Hit(evt.X, evt.Y) {
-				pid := -1
-				if evt.TouchID > 0 { // I hope touches can't be 0...
-					pid = evt.TouchID
-				} else {
-					pid = evt.ButtonID
-				}
-				evt.Widget = n.Widget
-				if gx, ok := n.Widget.(getters.X); ok {
-					evt.RelativeX = evt.X - gx.GetX()
-				}
-				if gy, ok := n.Widget.(getters.Y); ok {
-					evt.RelativeY = evt.Y - gy.GetY()
-				}
+			l.startGesture(target, evt)
+		}
+		if evt.DefaultPrevented() {
+			break
+		}
+		// Focus the nearest focusable node, or unfocus if there is none.
+		var focus *Node
+		for n := target; n != nil; n = n.Parent {
+			if n.FocusIndex > 0 {
+				focus = n
+				break
+			}
+		}
+		l.setFocus(focus, evt.Timestamp, evt.Pointer)
+	case *events.PointerRelease:
+		pid := evt.ID()
+		target := l.pointerTarget(evt.Pointer)
+		// The nearest node that was pressed and then released upon receives a PointerPressed event.
+		var pressed *Node
+		if target != nil {
+			l.dispatchEvent(target, evt, func(n *Node) {
 				if n.OnPointerRelease != nil {
 					n.OnPointerRelease(evt)
 				}
+				if evt.Canceled() {
+					return
+				}
 				if hrelease, ok := n.Widget.(receivers.PointerRelease); ok {
 					hrelease.HandlePointerRelease(evt)
 				}
+			})
+			// The node under the pointer is used rather than any captor, so that a captured pointer released outside of what it pressed is not a press.
+			for n := l.hitTarget(evt.X, evt.Y); n != nil; n = n.Parent {
 				if l.currentState.isPressed(n, pid) {
-					l.currentState.removePressed(n, pid)
-					pointerPressedEvent := &events.PointerPressed{
-						TargetWidget: events.TargetWidget{Widget: n.Widget},
-						Duration:     evt.Duration,
-						Timestamp:    evt.Timestamp,
-						Pointer:      evt.Pointer,
-					}
-					if n.OnPointerPressed != nil {
-						n.OnPointerPressed(pointerPressedEvent)
-					}
-					if hpress, ok := n.Widget.(receivers.PointerPressed); ok {
-						hpress.HandlePointerPressed(pointerPressedEvent)
-					}
+					pressed = n
+					break
 				}
 			}
+			// Nodes that received the release do not receive it again as a global release.
+			for n := target; n != nil; n = n.Parent {
+				l.currentState.removePressed(n, pid)
+			}
 		}
-	}
-}
-
-// processEvent is called after processNodeEvent and does any further handling beyond what the nodes can handle.
-func (l *Layout) processEvent(e Event) {
-	switch evt := e.(type) {
-	case *events.PointerPress:
-		// Unfocus the current focused node if we have a press that does not hit it.
-		if l.focusedNode != nil {
-			if hit, ok := l.focusedNode.Widget.(HitChecker); ok {
-				if hit.Hit(evt.X, evt.Y) {
-					// We hit the focused node, so we don't need to do anything.
-					break
-				}
-				unfocusEvent := &events.Unfocus{
-					TargetWidget: events.TargetWidget{Widget: l.focusedNode.Widget},
-					Timestamp:    events.Timestamp{Timestamp: time.Now()},
+		// A drag ends with a drop rather than a press.
+		if d := l.drag; d != nil && d.pid == pid {
+			l.releaseDrag(d, evt.Pointer, evt.Timestamp)
+			pressed = nil
+		}
+		if pressed != nil {
+			pointerPressedEvent := &events.PointerPressed{
+				Duration:  evt.Duration,
+				Timestamp: evt.Timestamp,
+				Pointer:   evt.Pointer,
+			}
+			l.dispatchEvent(pressed, pointerPressedEvent, func(n *Node) {
+				if n.OnPointerPressed != nil {
+					n.OnPointerPressed(pointerPressedEvent)
 				}
-				if l.focusedNode.OnUnfocus != nil {
-					l.focusedNode.OnUnfocus(unfocusEvent)
+				if pointerPressedEvent.Canceled() {
+					return
 				}
-				if hunfocus, ok := l.focusedNode.Widget.(receivers.Unfocus); ok {
-					hunfocus.HandleUnfocus(unfocusEvent)
+				if hpress, ok := n.Widget.(receivers.PointerPressed); ok {
+					hpress.HandlePointerPressed(pointerPressedEvent)
 				}
-				l.focusedNode = nil
-			}
-		}
-	case *events.PointerRelease:
-		pid := -1
-		if evt.TouchID > 0 { // I hope touches can't be 0...
-			pid = evt.TouchID
-		} else {
-			pid = evt.ButtonID
+			})
 		}
+		l.endGesture(pressed, evt)
 		// Clear out any held releases.
 		l.Nodes.ForEach(func(n *Node) bool {
 			if l.currentState.isPressed(n, pid) {
-				evt.Widget = n.Widget
-				if gx, ok := n.Widget.(getters.X); ok {
-					evt.RelativeX = evt.X - gx.GetX()
-				}
-				if gy, ok := n.Widget.(getters.Y); ok {
-					evt.RelativeY = evt.Y - gy.GetY()
-				}
+				retarget(evt, n, n, events.PhaseNone)
 				if n.OnPointerGlobalRelease != nil {
 					n.OnPointerGlobalRelease(evt)
 				}
@@ -987,74 +1085,125 @@ func (l *Layout) processEvent(e Event) {
 			return false
 		})
 		l.currentState.removePressedID(pid)
-	case *events.PointerMove:
-		pid := -1
-		if evt.TouchID > 0 { // I hope touches can't be 0...
-			pid = evt.TouchID
-		} else {
-			pid = evt.ButtonID
-		}
-		// Handle any global move handlers that were pressed.
-		l.Nodes.ForEach(func(n *Node) bool {
-			evt.Widget = n.Widget
-			if gx, ok := n.Widget.(getters.X); ok {
-				evt.RelativeX = evt.X - gx.GetX()
-			}
-			if gy, ok := n.Widget.(getters.Y); ok {
-				evt.RelativeY = evt.Y - gy.GetY()
-			}
-			if l.currentState.isPressed(n, pid) {
-				if n.OnPointerGlobalMove != nil {
-					n.OnPointerGlobalMove(evt)
+		l.ReleasePointerCapture(pid)
+	case *events.PointerWheel:
+		if target := l.hitTarget(evt.X, evt.Y); target != nil {
+			l.dispatchEvent(target, evt, func(n *Node) {
+				if n.OnPointerWheel != nil {
+					n.OnPointerWheel(evt)
 				}
-				if hmove, ok := n.Widget.(receivers.PointerGlobalMove); ok {
-					hmove.HandlePointerGlobalMove(evt)
+				if evt.Canceled() {
+					return
 				}
-			}
-			return false
-		})
+				if hwheel, ok := n.Widget.(receivers.PointerWheel); ok {
+					hwheel.HandlePointerWheel(evt)
+				}
+			})
+		}
 	case *events.KeyPress:
 		if l.focusedNode != nil {
-			evt.Widget = l.focusedNode.Widget
-			if l.focusedNode.OnKeyPress != nil {
-				l.focusedNode.OnKeyPress(evt)
-			}
-			if evt.Canceled() {
-				break
-			}
-			if n, ok := l.focusedNode.Widget.(receivers.KeyPress); ok {
-				n.HandleKeyPress(evt)
-			}
+			l.dispatchEvent(l.focusedNode, evt, func(n *Node) {
+				if n.OnKeyPress != nil {
+					n.OnKeyPress(evt)
+				}
+				if evt.Canceled() {
+					return
+				}
+				if hkey, ok := n.Widget.(receivers.KeyPress); ok {
+					hkey.HandleKeyPress(evt)
+				}
+			})
+		}
+		if !evt.DefaultPrevented() {
+			l.processShortcut(evt)
+		}
+		if !evt.DefaultPrevented() {
+			l.processFocusKey(evt)
 		}
 	case *events.KeyRelease:
 		if l.focusedNode != nil {
-			evt.Widget = l.focusedNode.Widget
-			if l.focusedNode.OnKeyRelease != nil {
-				l.focusedNode.OnKeyRelease(evt)
-			}
-			if evt.Canceled() {
-				break
-			}
-			if n, ok := l.focusedNode.Widget.(receivers.KeyRelease); ok {
-				n.HandleKeyRelease(evt)
-			}
+			l.dispatchEvent(l.focusedNode, evt, func(n *Node) {
+				if n.OnKeyRelease != nil {
+					n.OnKeyRelease(evt)
+				}
+				if evt.Canceled() {
+					return
+				}
+				if hkey, ok := n.Widget.(receivers.KeyRelease); ok {
+					hkey.HandleKeyRelease(evt)
+				}
+			})
 		}
 	case *events.KeyInput:
 		if l.focusedNode != nil {
-			evt.Widget = l.focusedNode.Widget
-			if l.focusedNode.OnKeyInput != nil {
-				l.focusedNode.OnKeyInput(evt)
-			}
-			if evt.Canceled() {
-				break
-			}
-			if n, ok := l.focusedNode.Widget.(receivers.KeyInput); ok {
-				n.HandleKeyInput(evt)
-			}
+			l.dispatchEvent(l.focusedNode, evt, func(n *Node) {
+				if n.OnKeyInput != nil {
+					n.OnKeyInput(evt)
+				}
+				if evt.Canceled() {
+					return
+				}
+				if hkey, ok := n.Widget.(receivers.KeyInput); ok {
+					hkey.HandleKeyInput(evt)
+				}
+			})
+		}
+	case *events.GamepadButtonPress:
+		if l.focusedNode != nil {
+			l.dispatchEvent(l.focusedNode, evt, func(n *Node) {
+				if n.OnGamepadButtonPress != nil {
+					n.OnGamepadButtonPress(evt)
+				}
+				if evt.Canceled() {
+					return
+				}
+				if hbutton, ok := n.Widget.(receivers.GamepadButtonPress); ok {
+					hbutton.HandleGamepadButtonPress(evt)
+				}
+			})
+		}
+		if !evt.DefaultPrevented() {
+			l.processGamepadAction(evt)
+		}
+	case *events.GamepadButtonRelease:
+		if l.focusedNode != nil {
+			l.dispatchEvent(l.focusedNode, evt, func(n *Node) {
+				if n.OnGamepadButtonRelease != nil {
+					n.OnGamepadButtonRelease(evt)
+				}
+				if evt.Canceled() {
+					return
+				}
+				if hbutton, ok := n.Widget.(receivers.GamepadButtonRelease); ok {
+					hbutton.HandleGamepadButtonRelease(evt)
+				}
+			})
+		}
+	case *events.GamepadAxis:
+		if l.focusedNode != nil {
+			l.dispatchEvent(l.focusedNode, evt, func(n *Node) {
+				if n.OnGamepadAxis != nil {
+					n.OnGamepadAxis(evt)
+				}
+				if evt.Canceled() {
+					return
+				}
+				if haxis, ok := n.Widget.(receivers.GamepadAxis); ok {
+					haxis.HandleGamepadAxis(evt)
+				}
+			})
 		}
 	}
 }
 
+// now returns the current time from the Clock, or from time.Now if there is none.
+func (l *Layout) now() time.Time {
+	if l.Clock != nil {
+		return l.Clock()
+	}
+	return time.Now()
+}
+
 func fallback[T comparable](a, b T) T {
 	if *new(T) == a {
 		return b
@@ -1106,86 +1255,3 @@ func stringToColor(s string, fallback color.Color) color.Color {
 	}
 	return color.Black
 }
-
-func stringToPosition(l *Layout, s string, outer float64, vertical bool) (value float64, relative bool) {
-	if s == "" {
-		return 0, false
-	}
-
-	stream := tokenParser.ParseString(s)
-	defer stream.Close()
-	var relation, unit int
-	var target string
-	var val float64
-
-	for stream.IsValid() {
-		token := stream.CurrentToken()
-		if token.Is(tUnit) {
-			switch token.ValueString() {
-			case "%":
-				unit = unitPercentage
-			case "vw":
-				unit = unitVW
-			case "vh":
-				unit = unitVH
-			}
-		} else if token.Is(tRelation) {
-			switch token.ValueString() {
-			case "at":
-				relation = relationAt
-			case "after":
-				relation = relationAfter
-			case "of":
-				relation = relationOf
-			}
-		} else if token.Is(tokenizer.TokenKeyword) {
-			target = token.ValueString()
-		} else if token.Is(tokenizer.TokenFloat) {
-			val = token.ValueFloat64()
-		} else if token.Is(tokenizer.TokenInteger) {
-			val = float64(token.ValueInt64())
-		}
-		stream.GoNext()
-	}
-
-	switch relation {
-	case relationNone:
-		// No relations, we be lonely bois.
-		switch unit {
-		case unitPixels:
-			return val, false
-		case unitPercentage:
-			return (val / 100) * outer, false // This feels like it should be true, but we only use relative for X/Y outer adjustments...
-		case unitVW:
-			// TODO
-		case unitVH:
-			// TODO
-		default:
-			panic("oh no")
-		}
-	case relationAfter:
-		after := l.GetByID(target)
-		if vertical {
-			return after.y + after.height, true
-		}
-		return after.x + after.width, true
-	case relationAt:
-		at := l.GetByID(target)
-		if vertical {
-			return at.y, true
-		}
-		return at.x, true
-	case relationOf:
-		val = val / 100
-		of := l.GetByID(target)
-		if vertical {
-			return of.height * val, true
-		}
-		return of.width * val, true
-	default:
-		panic("oh no")
-	}
-
-	// Dis do be unreachable.
-	return 0, false
-}
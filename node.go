@@ -1,5 +1,7 @@
 package rebui
 
+import "github.com/kettek/rebui/events"
+
 // Node is a parseable structure used for determining element position, style, and beyond.
 type Node struct {
 	ID              string
@@ -30,6 +32,21 @@ type Node struct {
 	Image           string // ???
 	Source          string // TODO: maybe merge with Image? This is only used by Templates atm.
 	FocusIndex      int
+	Draggable       bool    // Draggable allows this node to be dragged and dropped on nodes that accept its DragType.
+	DragType        string  // DragType is the kind of payload dragged from this node.
+	Accepts         string  // Accepts is the space-separated DragTypes that may be dropped on this node.
+	Shortcut        string  // Shortcut is a key chord, such as "Ctrl+S", that activates this node while it is visible.
+	Cursor          Cursor  // Cursor is the shape of the mouse cursor while hovering this node, such as "text" or "ew-resize". If empty, the parent's cursor is used.
+	Padding         string  // Padding insets the area that children are laid out within.
+	Gap             string  // Gap is the space between children of container widgets.
+	Grow            float64 // Grow is the weight used to grow this node to fill free space within a container widget.
+	Shrink          float64 // Shrink is the weight used to shrink this node when it overflows a container widget. A weight of 0 prevents shrinking.
+	Columns         string  // Columns are the space-separated column tracks of a grid, such as "100 1fr 2fr".
+	Rows            string  // Rows are the space-separated row tracks of a grid.
+	Column          int     // Column is the 1-based grid column to place this node in. 0 places it automatically.
+	Row             int     // Row is the 1-based grid row to place this node in. 0 places it automatically.
+	ColumnSpan      int     // ColumnSpan is the number of grid columns this node covers, defaulting to 1.
+	RowSpan         int     // RowSpan is the number of grid rows this node covers, defaulting to 1.
 	Children        Nodes
 	Hidden          bool
 	Disabled        bool
@@ -37,6 +54,8 @@ type Node struct {
 	// Note: The following two values are hacky but are necessary for our implementation of templates...
 	isRelativeX bool // Whether or not this element uses "after/before/at/of" for X
 	isRelativeY bool // Whether or not this element uses "after/before/at/of" for Y
+	paddingX    float64
+	paddingY    float64
 	nodeHooks
 }
 
@@ -93,6 +112,11 @@ func (ns *Nodes) ForEachDeepest(cb func(*Node) bool) bool {
 	return false
 }
 
+// Bounds returns the node's position and size as of the last layout.
+func (n *Node) Bounds() (x, y, width, height float64) {
+	return n.x, n.y, n.width, n.height
+}
+
 // getNodeByID returns any node that has the passed ID, including any nested children.
 func (n *Node) getNodeByID(id string) *Node {
 	if n.ID == id {
@@ -129,15 +153,36 @@ type nodeHooks struct {
 	OnPointerPressed       func(EventPointerPressed)
 	OnPointerGlobalRelease func(EventPointerRelease)
 	OnPointerGlobalMove    func(EventPointerMove)
+	OnPointerWheel         func(EventPointerWheel)
+	OnPointerTap           func(EventPointerTap)
+	OnPointerDoubleClick   func(EventPointerDoubleClick)
+	OnPointerLongPress     func(EventPointerLongPress)
+	OnPinch                func(EventPinch)
+	OnRotate               func(EventRotate)
+	OnPan                  func(EventPan)
+	OnDragStart            func(EventDragStart)
+	OnDragEnter            func(EventDragEnter)
+	OnDragOver             func(EventDragOver)
+	OnDragLeave            func(EventDragLeave)
+	OnDrop                 func(EventDrop)
+	OnDragEnd              func(EventDragEnd)
+	OnGotCapture           func(EventGotCapture)
+	OnLostCapture          func(EventLostCapture)
 	OnFocus                func(EventFocus)
 	OnUnfocus              func(EventUnfocus)
 	OnKeyPress             func(EventKeyPress)
 	OnKeyRelease           func(EventKeyRelease)
 	OnKeyInput             func(EventKeyInput)
+	OnGamepadButtonPress   func(EventGamepadButtonPress)
+	OnGamepadButtonRelease func(EventGamepadButtonRelease)
+	OnGamepadAxis          func(EventGamepadAxis)
+	OnActivate             func(EventActivate)
+	OnCancel               func(EventCancel)
+	OnCapture              func(Event) // Receives events of any type during their capture phase, as they are dispatched down to a descendant.
 }
 
 // pressedNode is a convenience struct that corresponds a given node with a pointer ID.
 type pressedNode struct {
 	node *Node
-	id   int
+	id   events.PointerID
 }
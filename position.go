@@ -0,0 +1,444 @@
+package rebui
+
+import (
+	"fmt"
+
+	"github.com/kettek/tokenizer"
+)
+
+// positionToken is a copy of a token from the tokenizer's stream, as the stream's tokens are recycled once it is closed.
+type positionToken struct {
+	key   tokenizer.TokenKey
+	value string
+	num   float64
+}
+
+func (t positionToken) is(key tokenizer.TokenKey, value string) bool {
+	return t.key == key && t.value == value
+}
+
+// positionParser is a recursive descent parser that evaluates position strings such as "50%", "after button1", or "calc(100% - 16)".
+//
+//	expression := term { ("+" | "-") term }
+//	term       := unary { ("*" | "/") unary }
+//	unary      := ("-" | "+") unary | primary
+//	primary    := "(" expression ")"
+//	            | function "(" expression { "," expression } ")"
+//	            | number [ "%" | "vw" | "vh" ] [ "of" id ]
+//	            | ( "after" | "at" | "before" ) id
+//	            | ( "left" | "right" | "top" | "bottom" | "center" ) "of" id
+//	            | ( "start" | "center" | "end" ) "at" id
+type positionParser struct {
+	layout   *Layout
+	src      string
+	tokens   []positionToken
+	pos      int
+	outer    float64 // The outer size that percentages are relative to.
+	self     float64 // The size of the node being positioned, used by "before" and "end at".
+	vertical bool    // Whether relations should use the vertical axis.
+	relative bool    // Whether any relation was used, meaning the result is an absolute coordinate.
+	validate bool    // Whether the expression is only being validated, in which case value-dependent errors are ignored.
+	refs     []positionRef
+}
+
+// positionRef is a reference to another node from within a position string.
+type positionRef struct {
+	node       *Node
+	positional bool // Whether the node's position is used, as opposed to only its size.
+}
+
+func tokenizePosition(s string) (tokens []positionToken) {
+	stream := tokenParser.ParseString(s)
+	defer stream.Close()
+	for stream.IsValid() {
+		token := stream.CurrentToken()
+		t := positionToken{key: token.Key(), value: token.ValueString()}
+		if token.Is(tokenizer.TokenFloat) {
+			t.num = token.ValueFloat64()
+		} else if token.Is(tokenizer.TokenInteger) {
+			t.num = float64(token.ValueInt64())
+		}
+		tokens = append(tokens, t)
+		stream.GoNext()
+	}
+	return
+}
+
+// evalPosition evaluates the given position string. If relative is true, the value is an absolute coordinate derived from another node rather than an offset within outer.
+func (l *Layout) evalPosition(s string, outer, self float64, vertical bool) (value float64, relative bool, err error) {
+	if s == "" {
+		return 0, false, nil
+	}
+	p := &positionParser{
+		layout:   l,
+		src:      s,
+		tokens:   tokenizePosition(s),
+		outer:    outer,
+		self:     self,
+		vertical: vertical,
+	}
+	return p.parse()
+}
+
+// validatePosition checks the given position string for syntax errors, bad units, and unresolved IDs.
+func (l *Layout) validatePosition(s string, vertical bool) error {
+	if s == "" {
+		return nil
+	}
+	p := &positionParser{
+		layout:   l,
+		src:      s,
+		tokens:   tokenizePosition(s),
+		vertical: vertical,
+		validate: true,
+	}
+	_, _, err := p.parse()
+	return err
+}
+
+// positionRefs returns the nodes referenced by the given position string. Unresolvable references are ignored.
+func (l *Layout) positionRefs(s string, vertical bool) []positionRef {
+	if s == "" {
+		return nil
+	}
+	p := &positionParser{
+		layout:   l,
+		src:      s,
+		tokens:   tokenizePosition(s),
+		vertical: vertical,
+		validate: true,
+	}
+	p.parse()
+	return p.refs
+}
+
+// nodePosition evaluates a position field of the given node, reporting any error against it.
+func (l *Layout) nodePosition(n *Node, field, s string, outer, self float64, vertical bool) (value float64, relative bool) {
+	value, relative, err := l.evalPosition(s, outer, self, vertical)
+	if err != nil {
+		l.reportError(&l.layoutErrors, newNodeError(n, field, err))
+	}
+	return value, relative
+}
+
+func (p *positionParser) parse() (value float64, relative bool, err error) {
+	if value, err = p.parseExpression(); err != nil {
+		return 0, false, err
+	}
+	if p.pos < len(p.tokens) {
+		return 0, false, p.unexpected()
+	}
+	return value, p.relative, nil
+}
+
+func (p *positionParser) peek() positionToken {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return positionToken{}
+}
+
+func (p *positionParser) next() positionToken {
+	t := p.peek()
+	if p.pos < len(p.tokens) {
+		p.pos++
+	}
+	return t
+}
+
+func (p *positionParser) expect(value string) error {
+	if !p.peek().is(tOperator, value) {
+		return p.unexpected()
+	}
+	p.pos++
+	return nil
+}
+
+func (p *positionParser) unexpected() error {
+	if p.pos >= len(p.tokens) {
+		return fmt.Errorf("%w: unexpected end of %q", ErrBadExpression, p.src)
+	}
+	return fmt.Errorf("%w: unexpected %q in %q", ErrBadExpression, p.tokens[p.pos].value, p.src)
+}
+
+func (p *positionParser) parseExpression() (float64, error) {
+	v, err := p.parseTerm()
+	if err != nil {
+		return 0, err
+	}
+	for {
+		if p.peek().is(tOperator, "+") {
+			p.pos++
+			v2, err := p.parseTerm()
+			if err != nil {
+				return 0, err
+			}
+			v += v2
+		} else if p.peek().is(tOperator, "-") {
+			p.pos++
+			v2, err := p.parseTerm()
+			if err != nil {
+				return 0, err
+			}
+			v -= v2
+		} else {
+			return v, nil
+		}
+	}
+}
+
+func (p *positionParser) parseTerm() (float64, error) {
+	v, err := p.parseUnary()
+	if err != nil {
+		return 0, err
+	}
+	for {
+		if p.peek().is(tOperator, "*") {
+			p.pos++
+			v2, err := p.parseUnary()
+			if err != nil {
+				return 0, err
+			}
+			v *= v2
+		} else if p.peek().is(tOperator, "/") {
+			p.pos++
+			v2, err := p.parseUnary()
+			if err != nil {
+				return 0, err
+			}
+			if v2 == 0 && !p.validate {
+				return 0, fmt.Errorf("%w: division by zero in %q", ErrBadExpression, p.src)
+			}
+			v /= v2
+		} else {
+			return v, nil
+		}
+	}
+}
+
+func (p *positionParser) parseUnary() (float64, error) {
+	if p.peek().is(tOperator, "-") {
+		p.pos++
+		v, err := p.parseUnary()
+		return -v, err
+	} else if p.peek().is(tOperator, "+") {
+		p.pos++
+		return p.parseUnary()
+	}
+	return p.parsePrimary()
+}
+
+func (p *positionParser) parsePrimary() (float64, error) {
+	t := p.peek()
+	switch {
+	case t.is(tOperator, "("):
+		p.pos++
+		v, err := p.parseExpression()
+		if err != nil {
+			return 0, err
+		}
+		return v, p.expect(")")
+	case t.key == tokenizer.TokenKeyword && p.pos+1 < len(p.tokens) && p.tokens[p.pos+1].is(tOperator, "("):
+		return p.parseFunction()
+	case t.key == tokenizer.TokenKeyword && p.pos+1 < len(p.tokens) && p.tokens[p.pos+1].key == tRelation:
+		return p.parseAnchor()
+	case t.key == tokenizer.TokenInteger || t.key == tokenizer.TokenFloat:
+		return p.parseValue()
+	case t.key == tRelation:
+		return p.parseRelation()
+	}
+	return 0, p.unexpected()
+}
+
+func (p *positionParser) parseFunction() (float64, error) {
+	name := p.next().value
+	p.pos++ // Skip "(".
+
+	var args []float64
+	for {
+		v, err := p.parseExpression()
+		if err != nil {
+			return 0, err
+		}
+		args = append(args, v)
+		if p.peek().is(tOperator, ",") {
+			p.pos++
+			continue
+		}
+		if err := p.expect(")"); err != nil {
+			return 0, err
+		}
+		break
+	}
+
+	switch name {
+	case funcCalc:
+		if len(args) != 1 {
+			return 0, fmt.Errorf("%w: %s expects 1 argument in %q", ErrBadExpression, name, p.src)
+		}
+		return args[0], nil
+	case funcMin:
+		v := args[0]
+		for _, a := range args[1:] {
+			v = min(v, a)
+		}
+		return v, nil
+	case funcMax:
+		v := args[0]
+		for _, a := range args[1:] {
+			v = max(v, a)
+		}
+		return v, nil
+	case funcClamp:
+		if len(args) != 3 {
+			return 0, fmt.Errorf("%w: %s expects 3 arguments in %q", ErrBadExpression, name, p.src)
+		}
+		return max(args[0], min(args[1], args[2])), nil
+	}
+	return 0, fmt.Errorf("%w: unknown function %q in %q", ErrBadExpression, name, p.src)
+}
+
+func (p *positionParser) parseValue() (float64, error) {
+	val := p.next().num
+
+	unit := unitPixels
+	if t := p.peek(); t.key == tUnit {
+		p.pos++
+		switch t.value {
+		case "%":
+			unit = unitPercentage
+		case "vw":
+			unit = unitVW
+		case "vh":
+			unit = unitVH
+		}
+	} else if t.key == tokenizer.TokenKeyword {
+		return 0, fmt.Errorf("%w %q in %q", ErrBadUnit, t.value, p.src)
+	}
+
+	if p.peek().is(tRelation, "of") {
+		p.pos++
+		of, err := p.parseTarget(false)
+		if err != nil {
+			return 0, err
+		}
+		p.relative = true
+		if p.vertical {
+			return of.height * val / 100, nil
+		}
+		return of.width * val / 100, nil
+	}
+
+	switch unit {
+	case unitPercentage:
+		return (val / 100) * p.outer, nil // This feels like it should be relative, but we only use relative for X/Y outer adjustments...
+	case unitVW:
+		return (val / 100) * p.layout.viewportWidth, nil
+	case unitVH:
+		return (val / 100) * p.layout.viewportHeight, nil
+	}
+	return val, nil
+}
+
+func (p *positionParser) parseRelation() (float64, error) {
+	relation := relationNone
+	switch p.next().value {
+	case "after":
+		relation = relationAfter
+	case "at":
+		relation = relationAt
+	case "before":
+		relation = relationBefore
+	default:
+		p.pos--
+		return 0, p.unexpected()
+	}
+
+	target, err := p.parseTarget(true)
+	if err != nil {
+		return 0, err
+	}
+	p.relative = true
+
+	switch relation {
+	case relationAfter:
+		if p.vertical {
+			return target.y + target.height, nil
+		}
+		return target.x + target.width, nil
+	case relationBefore:
+		if p.vertical {
+			return target.y - p.self, nil
+		}
+		return target.x - p.self, nil
+	default:
+		if p.vertical {
+			return target.y, nil
+		}
+		return target.x, nil
+	}
+}
+
+// parseAnchor parses an edge of a target, such as "right of toolbar", or an alignment against a target, such as "end at toolbar".
+func (p *positionParser) parseAnchor() (float64, error) {
+	anchor := p.next().value
+	relation := p.next().value
+
+	target, err := p.parseTarget(true)
+	if err != nil {
+		return 0, err
+	}
+	p.relative = true
+
+	start, size := target.x, target.width
+	if p.vertical {
+		start, size = target.y, target.height
+	}
+
+	switch relation {
+	case "of":
+		switch anchor {
+		case anchorLeft, anchorTop:
+			if (anchor == anchorTop) != p.vertical {
+				break
+			}
+			return start, nil
+		case anchorRight, anchorBottom:
+			if (anchor == anchorBottom) != p.vertical {
+				break
+			}
+			return start + size, nil
+		case anchorCenter:
+			return start + size/2, nil
+		}
+	case "at":
+		switch anchor {
+		case anchorStart:
+			return start, nil
+		case anchorCenter:
+			return start + (size-p.self)/2, nil
+		case anchorEnd:
+			return start + size - p.self, nil
+		}
+	}
+	axis := "horizontal"
+	if p.vertical {
+		axis = "vertical"
+	}
+	return 0, fmt.Errorf("%w: %q cannot be used for %s positions in %q", ErrBadExpression, anchor+" "+relation, axis, p.src)
+}
+
+// parseTarget parses and resolves a target ID. If positional is true, the target's position is used in addition to its size.
+func (p *positionParser) parseTarget(positional bool) (*Node, error) {
+	t := p.peek()
+	if t.key != tokenizer.TokenKeyword {
+		return nil, p.unexpected()
+	}
+	p.pos++
+	n := p.layout.GetByID(t.value)
+	if n == nil {
+		return nil, fmt.Errorf("%w %q in %q", ErrUnresolvedID, t.value, p.src)
+	}
+	p.refs = append(p.refs, positionRef{node: n, positional: positional})
+	return n, nil
+}
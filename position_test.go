@@ -0,0 +1,108 @@
+package rebui_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/kettek/rebui"
+	"github.com/kettek/rebui/rebuitest"
+	_ "github.com/kettek/rebui/widgets"
+)
+
+// newCollectingHarness creates a 400 by 300 Harness whose Layout collects errors rather than logging them.
+func newCollectingHarness(t *testing.T, nodes ...rebui.Node) *rebuitest.Harness {
+	l := &rebui.Layout{CollectErrors: true}
+	for _, n := range nodes {
+		l.AddNode(n)
+	}
+	return rebuitest.NewFromLayout(t, l, 400, 300)
+}
+
+func TestPositionExpressions(t *testing.T) {
+	tests := []struct {
+		width, height string
+		w, h          float64
+	}{
+		// Precedence and associativity.
+		{"10 + 2 * 5", "1", 20, 1},
+		{"(10 + 2) * 5", "1", 60, 1},
+		{"100 - 20 - 30", "1", 50, 1},
+		{"100 / 4 / 5", "1", 5, 1},
+		{"100 - 40 / 2 * 3", "1", 40, 1},
+		// Unary operators.
+		{"-10 + 30", "1", 20, 1},
+		{"2 * -5 + 20", "1", 10, 1},
+		{"-(10 - 30)", "1", 20, 1},
+		{"--10", "+-+10 + 20", 10, 10},
+		// Units.
+		{"50%", "50%", 200, 150},
+		{"calc(50% - 10)", "calc(100% / 3)", 190, 100},
+		{"10vw", "10vw", 40, 40},
+		{"10vh", "10vh", 30, 30},
+		{"calc(10vw + 10vh)", "1", 70, 1},
+		// Functions.
+		{"min(100, 50%, 300)", "max(10, 25%)", 100, 75},
+		{"min(50%)", "max(-5, 5)", 200, 5},
+		{"clamp(50, 10%, 80)", "clamp(10, 50%, 80)", 50, 80},
+		{"clamp(10, 5%, 80)", "max(10, min(20, 30)) * 2", 20, 40},
+	}
+	for _, tt := range tests {
+		h := newCollectingHarness(t, rebui.Node{
+			Type:   "Area",
+			ID:     "area",
+			Width:  tt.width,
+			Height: tt.height,
+		})
+		if errs := h.Layout.Errors(); len(errs) != 0 {
+			t.Errorf("%q by %q: expected no errors, got %v", tt.width, tt.height, errs)
+			continue
+		}
+		if r := h.Rect("area"); r.Width != tt.w || r.Height != tt.h {
+			t.Errorf("%q by %q: expected %gx%g, got %gx%g", tt.width, tt.height, tt.w, tt.h, r.Width, r.Height)
+		}
+	}
+}
+
+func TestPositionErrors(t *testing.T) {
+	tests := []struct {
+		width     string
+		err       error
+		validated bool // If Validate reports the error as well. Errors that depend upon values are only found during layout.
+	}{
+		{"10 / 0", rebui.ErrBadExpression, false},
+		{"100 / (50% - 200)", rebui.ErrBadExpression, false},
+		{"10 / -(5 - 5)", rebui.ErrBadExpression, false},
+		{"10 +", rebui.ErrBadExpression, true},
+		{"(10", rebui.ErrBadExpression, true},
+		{"10)", rebui.ErrBadExpression, true},
+		{"10 10", rebui.ErrBadExpression, true},
+		{"* 10", rebui.ErrBadExpression, true},
+		{"calc(1, 2)", rebui.ErrBadExpression, true},
+		{"clamp(1, 2)", rebui.ErrBadExpression, true},
+		{"min()", rebui.ErrBadExpression, true},
+		{"foo(1)", rebui.ErrBadExpression, true},
+		{"10em", rebui.ErrBadUnit, true},
+	}
+	for _, tt := range tests {
+		h := newCollectingHarness(t, rebui.Node{
+			Type:   "Area",
+			ID:     "area",
+			Width:  tt.width,
+			Height: "10",
+		})
+		errs := h.Layout.Errors()
+		if len(errs) == 0 {
+			t.Errorf("%q: expected %v, got no errors", tt.width, tt.err)
+			continue
+		}
+		for _, err := range errs {
+			var nerr *rebui.NodeError
+			if !errors.Is(err, tt.err) || !errors.As(err, &nerr) || nerr.NodeID != "area" || nerr.Field != "Width" {
+				t.Errorf("%q: expected %v in the Width of area, got %v", tt.width, tt.err, err)
+			}
+		}
+		if errs := h.Layout.Validate(); (len(errs) != 0) != tt.validated {
+			t.Errorf("%q: expected Validate to report it to be %v, got %v", tt.width, tt.validated, errs)
+		}
+	}
+}
@@ -0,0 +1,280 @@
+// Package rebuitest provides a headless harness for driving and inspecting a rebui Layout from tests.
+package rebuitest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/kettek/rebui"
+)
+
+// Rect is the computed position and size of a node.
+type Rect struct {
+	X, Y, Width, Height float64
+}
+
+// Center returns the center point of the rect.
+func (r Rect) Center() (x, y float64) {
+	return r.X + r.Width/2, r.Y + r.Height/2
+}
+
+// Harness drives a Layout with a ScriptedInput against a fixed LayoutContext. Each interaction runs one or more frames, where a frame is a call to Layout.Layout followed by Layout.Update.
+type Harness struct {
+	T       testing.TB
+	Layout  *rebui.Layout
+	Input   *rebui.ScriptedInput
+	Context rebui.LayoutContext
+	// Time is the current time of the Layout's Clock. It advances by FrameDuration before each frame and otherwise only changes with Advance, so that timing, such as of long presses and key repeats, depends only on the frames run.
+	Time time.Time
+	// FrameDuration is how far Time advances before each frame.
+	FrameDuration time.Duration
+}
+
+// Epoch is the time that a Harness's clock starts at.
+var Epoch = time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// New creates a Harness that adds and generates the given nodes and lays them out within a width by height viewport.
+func New(t testing.TB, width, height float64, nodes ...rebui.Node) *Harness {
+	l := &rebui.Layout{}
+	for _, n := range nodes {
+		l.AddNode(n)
+	}
+	return NewFromLayout(t, l, width, height)
+}
+
+// NewFromJSON creates a Harness from a JSON layout source.
+func NewFromJSON(t testing.TB, src string, width, height float64) *Harness {
+	t.Helper()
+	l, err := rebui.NewLayout(src)
+	if err != nil {
+		t.Fatalf("rebuitest: parsing layout: %v", err)
+	}
+	l.Generate()
+	return NewFromLayout(t, l, width, height)
+}
+
+// NewFromLayout creates a Harness around an existing, already generated, Layout. The Layout's Input is replaced with a ScriptedInput and its Clock with the Harness's Time, which starts at Epoch and advances by a sixtieth of a second each frame.
+func NewFromLayout(t testing.TB, l *rebui.Layout, width, height float64) *Harness {
+	h := &Harness{
+		T:      t,
+		Layout: l,
+		Input:  &rebui.ScriptedInput{},
+		Context: rebui.LayoutContext{
+			OuterWidth:  width,
+			OuterHeight: height,
+		},
+		Time:          Epoch,
+		FrameDuration: time.Second / 60,
+	}
+	l.Input = h.Input
+	l.Clock = h.Now
+	l.Layout(h.Context)
+	return h
+}
+
+// Now returns the harness's Time. It is used as the Layout's Clock.
+func (h *Harness) Now() time.Time {
+	return h.Time
+}
+
+// Advance moves the harness's Time forward by the given duration without running a frame.
+func (h *Harness) Advance(d time.Duration) {
+	h.Time = h.Time.Add(d)
+}
+
+// Wait runs frames until at least the given duration has passed. If FrameDuration is not positive, Time is advanced by the duration and a single frame is run.
+func (h *Harness) Wait(d time.Duration) {
+	if h.FrameDuration <= 0 {
+		h.Advance(d)
+		h.Step()
+		return
+	}
+	end := h.Time.Add(d)
+	for h.Time.Before(end) {
+		h.Step()
+	}
+}
+
+// Step advances Time by FrameDuration and runs a single frame.
+func (h *Harness) Step() {
+	h.Advance(h.FrameDuration)
+	h.Layout.Layout(h.Context)
+	h.Layout.Update()
+}
+
+// Steps runs the given number of frames.
+func (h *Harness) Steps(count int) {
+	for i := 0; i < count; i++ {
+		h.Step()
+	}
+}
+
+// Node returns the node with the given ID, failing the test if it does not exist.
+func (h *Harness) Node(id string) *rebui.Node {
+	h.T.Helper()
+	n := h.Layout.GetByID(id)
+	if n == nil {
+		h.T.Fatalf("rebuitest: no node with ID %q", id)
+	}
+	return n
+}
+
+// Rect returns the computed rect of the node with the given ID.
+func (h *Harness) Rect(id string) Rect {
+	h.T.Helper()
+	x, y, w, hh := h.Node(id).Bounds()
+	return Rect{x, y, w, hh}
+}
+
+// Rects returns the computed rects of every node that has an ID, keyed by ID.
+func (h *Harness) Rects() map[string]Rect {
+	rects := make(map[string]Rect)
+	var walk func(ns rebui.Nodes)
+	walk = func(ns rebui.Nodes) {
+		for _, n := range ns {
+			if n.ID != "" {
+				x, y, w, hh := n.Bounds()
+				rects[n.ID] = Rect{x, y, w, hh}
+			}
+			walk(n.Children)
+		}
+	}
+	walk(h.Layout.Nodes)
+	return rects
+}
+
+// MoveTo moves the mouse cursor to the given position and runs a frame.
+func (h *Harness) MoveTo(x, y float64) {
+	h.Input.SetCursorPosition(int(x), int(y))
+	h.Step()
+}
+
+// Hover moves the mouse cursor to the center of the given node and runs a frame.
+func (h *Harness) Hover(id string) {
+	h.T.Helper()
+	h.MoveTo(h.Rect(id).Center())
+}
+
+// Press moves to the center of the given node and presses the given mouse button.
+func (h *Harness) Press(id string, mb ebiten.MouseButton) {
+	h.T.Helper()
+	h.Hover(id)
+	h.Input.PressMouseButton(mb)
+	h.Step()
+}
+
+// Release releases the given mouse button at the current cursor position.
+func (h *Harness) Release(mb ebiten.MouseButton) {
+	h.Input.ReleaseMouseButton(mb)
+	h.Step()
+}
+
+// Click presses and releases the left mouse button over the center of the given node.
+func (h *Harness) Click(id string) {
+	h.T.Helper()
+	h.ClickButton(id, ebiten.MouseButtonLeft)
+}
+
+// ClickButton presses and releases the given mouse button over the center of the given node.
+func (h *Harness) ClickButton(id string, mb ebiten.MouseButton) {
+	h.T.Helper()
+	h.Press(id, mb)
+	h.Release(mb)
+}
+
+// Drag presses the left mouse button over the center of the given node, moves the cursor by dx and dy over the given number of frames, then releases.
+func (h *Harness) Drag(id string, dx, dy float64, frames int) {
+	h.T.Helper()
+	if frames < 1 {
+		frames = 1
+	}
+	x, y := h.Rect(id).Center()
+	h.Press(id, ebiten.MouseButtonLeft)
+	for i := 1; i <= frames; i++ {
+		h.MoveTo(x+dx*float64(i)/float64(frames), y+dy*float64(i)/float64(frames))
+	}
+	h.Release(ebiten.MouseButtonLeft)
+}
+
+// Scroll moves the mouse cursor to the center of the given node, then turns the mouse wheel by dx and dy steps and runs a frame.
+func (h *Harness) Scroll(id string, dx, dy float64) {
+	h.T.Helper()
+	h.Hover(id)
+	h.Input.ScrollWheel(dx, dy)
+	h.Step()
+}
+
+// Tap starts and ends a touch over the center of the given node.
+func (h *Harness) Tap(id string, touchID ebiten.TouchID) {
+	h.T.Helper()
+	x, y := h.Rect(id).Center()
+	h.Input.SetTouch(touchID, int(x), int(y))
+	h.Step()
+	h.Input.ReleaseTouch(touchID)
+	h.Step()
+}
+
+// Pinch places two touches, with IDs 1 and 2, either side of the center of the given node and the given distance apart, moves them apart or together until they are the to distance apart over the given number of frames, then releases them.
+func (h *Harness) Pinch(id string, from, to float64, frames int) {
+	h.T.Helper()
+	if frames < 1 {
+		frames = 1
+	}
+	x, y := h.Rect(id).Center()
+	h.Input.SetTouch(1, int(x-from/2), int(y))
+	h.Input.SetTouch(2, int(x+from/2), int(y))
+	h.Step()
+	for i := 1; i <= frames; i++ {
+		d := from + (to-from)*float64(i)/float64(frames)
+		h.Input.SetTouch(1, int(x-d/2), int(y))
+		h.Input.SetTouch(2, int(x+d/2), int(y))
+		h.Step()
+	}
+	h.Input.ReleaseTouch(1)
+	h.Input.ReleaseTouch(2)
+	h.Step()
+}
+
+// KeyDown presses the given key and runs a frame.
+func (h *Harness) KeyDown(k ebiten.Key) {
+	h.Input.PressKey(k)
+	h.Step()
+}
+
+// KeyUp releases the given key and runs a frame.
+func (h *Harness) KeyUp(k ebiten.Key) {
+	h.Input.ReleaseKey(k)
+	h.Step()
+}
+
+// PressKey presses and releases the given key.
+func (h *Harness) PressKey(k ebiten.Key) {
+	h.KeyDown(k)
+	h.KeyUp(k)
+}
+
+// PressKeys holds the given keys in order, then releases them in reverse order. This is useful for chords such as ctrl+a.
+func (h *Harness) PressKeys(keys ...ebiten.Key) {
+	for _, k := range keys {
+		h.KeyDown(k)
+	}
+	for i := len(keys) - 1; i >= 0; i-- {
+		h.KeyUp(keys[i])
+	}
+}
+
+// PressGamepadButton presses and releases the given button of the gamepad with ID 0, running a frame after each.
+func (h *Harness) PressGamepadButton(b ebiten.StandardGamepadButton) {
+	h.T.Helper()
+	h.Input.PressGamepadButton(0, b)
+	h.Step()
+	h.Input.ReleaseGamepadButton(0, b)
+	h.Step()
+}
+
+// Type queues the runes of the given string as text input and runs a frame.
+func (h *Harness) Type(s string) {
+	h.Input.PushInputChars([]rune(s)...)
+	h.Step()
+}
@@ -0,0 +1,249 @@
+package rebuitest_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/kettek/rebui"
+	_ "github.com/kettek/rebui/defaults/font"
+	"github.com/kettek/rebui/rebuitest"
+	"github.com/kettek/rebui/widgets"
+)
+
+func TestRect(t *testing.T) {
+	h := rebuitest.New(t, 320, 240,
+		rebui.Node{
+			Type:   "Area",
+			ID:     "area1",
+			X:      "10",
+			Y:      "20",
+			Width:  "50%",
+			Height: "25%",
+		},
+		rebui.Node{
+			Type:   "Area",
+			ID:     "area2",
+			X:      "after area1",
+			Y:      "at area1",
+			Width:  "40",
+			Height: "100% of area1",
+		},
+	)
+
+	if r := h.Rect("area1"); r != (rebuitest.Rect{X: 10, Y: 20, Width: 160, Height: 60}) {
+		t.Errorf("expected area1 at 10,20 sized 160x60, got %+v", r)
+	}
+	if r := h.Rect("area2"); r != (rebuitest.Rect{X: 170, Y: 20, Width: 40, Height: 60}) {
+		t.Errorf("expected area2 at 170,20 sized 40x60, got %+v", r)
+	}
+	if x, y := h.Rect("area1").Center(); x != 90 || y != 50 {
+		t.Errorf("expected area1 center of 90,50, got %f,%f", x, y)
+	}
+
+	rects := h.Rects()
+	if len(rects) != 2 {
+		t.Fatalf("expected 2 rects, got %d", len(rects))
+	}
+	if rects["area2"] != h.Rect("area2") {
+		t.Errorf("expected Rects to match Rect for area2, got %+v", rects["area2"])
+	}
+	x, y, w, hh := h.Node("area2").Bounds()
+	if rects["area2"] != (rebuitest.Rect{X: x, Y: y, Width: w, Height: hh}) {
+		t.Errorf("expected Rects to match node bounds for area2, got %+v", rects["area2"])
+	}
+}
+
+func TestClick(t *testing.T) {
+	h := rebuitest.New(t, 320, 240,
+		rebui.Node{
+			Type:   "Button",
+			ID:     "button1",
+			Width:  "50%",
+			Height: "50",
+		},
+		rebui.Node{
+			Type:   "Button",
+			ID:     "button2",
+			X:      "50%",
+			Width:  "50%",
+			Height: "50",
+		},
+	)
+
+	var pressed1, pressed2 int
+	var x, y float64
+	h.Node("button1").OnPointerPressed = func(evt rebui.EventPointerPressed) {
+		pressed1++
+		x, y = evt.X, evt.Y
+	}
+	h.Node("button2").OnPointerPressed = func(evt rebui.EventPointerPressed) {
+		pressed2++
+	}
+
+	h.Click("button1")
+	if pressed1 != 1 || pressed2 != 0 {
+		t.Fatalf("expected only button1 to be pressed once, got %d and %d", pressed1, pressed2)
+	}
+	if x != 80 || y != 25 {
+		t.Errorf("expected button1 to be pressed at its center of 80,25, got %f,%f", x, y)
+	}
+
+	h.Click("button2")
+	h.Click("button2")
+	if pressed1 != 1 || pressed2 != 2 {
+		t.Errorf("expected button2 to be pressed twice, got %d and %d", pressed1, pressed2)
+	}
+}
+
+func TestDrag(t *testing.T) {
+	h := rebuitest.New(t, 320, 240, rebui.Node{
+		Type:   "Area",
+		ID:     "area",
+		Width:  "200",
+		Height: "200",
+	})
+
+	var pressX, pressY, releaseX, releaseY, moveX, moveY float64
+	var moves int
+	n := h.Node("area")
+	n.OnPointerPress = func(evt rebui.EventPointerPress) {
+		pressX, pressY = evt.X, evt.Y
+	}
+	n.OnPointerMove = func(evt rebui.EventPointerMove) {
+		if evt.ButtonID == int(ebiten.MouseButtonLeft) {
+			moves++
+			moveX += evt.DX
+			moveY += evt.DY
+		}
+	}
+	n.OnPointerRelease = func(evt rebui.EventPointerRelease) {
+		releaseX, releaseY = evt.X, evt.Y
+	}
+
+	h.Drag("area", 40, 20, 4)
+
+	if pressX != 100 || pressY != 100 {
+		t.Errorf("expected press at 100,100, got %f,%f", pressX, pressY)
+	}
+	if releaseX != 140 || releaseY != 120 {
+		t.Errorf("expected release at 140,120, got %f,%f", releaseX, releaseY)
+	}
+	if moves != 4 {
+		t.Errorf("expected 4 moves while pressed, got %d", moves)
+	}
+	if moveX != 40 || moveY != 20 {
+		t.Errorf("expected moves to total 40,20, got %f,%f", moveX, moveY)
+	}
+}
+
+func TestType(t *testing.T) {
+	h := rebuitest.New(t, 320, 240, rebui.Node{
+		Type:       "TextInput",
+		ID:         "input",
+		Width:      "200",
+		Height:     "20",
+		FocusIndex: 1,
+	})
+
+	var typed, changed string
+	h.Node("input").OnKeyInput = func(evt rebui.EventKeyInput) {
+		typed += string(evt.Rune)
+	}
+	h.Node("input").Widget.(*widgets.TextInput).OnChange = func(s string) {
+		changed = s
+	}
+
+	// Text is only received while focused.
+	h.Type("no")
+	if typed != "" {
+		t.Fatalf("expected no input before focusing, got %q", typed)
+	}
+
+	h.Click("input")
+	h.Type("hello")
+	if typed != "hello" {
+		t.Errorf("expected %q to be typed, got %q", "hello", typed)
+	}
+	if changed != "hello" {
+		t.Errorf("expected the input to hold %q, got %q", "hello", changed)
+	}
+}
+
+func TestScroll(t *testing.T) {
+	h := rebuitest.New(t, 320, 240, rebui.Node{
+		Type:   "Area",
+		ID:     "outer",
+		Width:  "100%",
+		Height: "100%",
+		Children: rebui.Nodes{
+			{
+				Type:   "Area",
+				ID:     "inner",
+				X:      "100",
+				Y:      "100",
+				Width:  "50",
+				Height: "50",
+			},
+		},
+	})
+
+	var order []string
+	var dx, dy float64
+	h.Node("inner").OnPointerWheel = func(evt rebui.EventPointerWheel) {
+		order = append(order, "inner")
+	}
+	h.Node("outer").OnPointerWheel = func(evt rebui.EventPointerWheel) {
+		order = append(order, "outer")
+		dx, dy = evt.DX, evt.DY
+	}
+
+	h.Scroll("inner", 1, -2)
+	if dx != 1 || dy != -2 {
+		t.Errorf("expected a wheel of 1,-2 to bubble to outer, got %f,%f", dx, dy)
+	}
+	if len(order) != 2 || order[0] != "inner" || order[1] != "outer" {
+		t.Errorf("expected the wheel to reach inner then outer, got %v", order)
+	}
+}
+
+func TestClock(t *testing.T) {
+	h := rebuitest.New(t, 320, 240, rebui.Node{
+		Type:       "Area",
+		ID:         "area",
+		Width:      "200",
+		Height:     "200",
+		FocusIndex: 1,
+	})
+
+	var presses, repeats int
+	var pressedAt time.Time
+	n := h.Node("area")
+	n.OnKeyPress = func(evt rebui.EventKeyPress) {
+		if evt.Repeat > 0 {
+			repeats++
+			return
+		}
+		presses++
+		pressedAt = evt.Timestamp.Timestamp
+	}
+
+	if !h.Time.Equal(rebuitest.Epoch) {
+		t.Fatalf("expected the clock to start at %v, got %v", rebuitest.Epoch, h.Time)
+	}
+
+	h.Click("area")
+	h.KeyDown(ebiten.KeyA)
+	if presses != 1 || !pressedAt.Equal(h.Time) {
+		t.Fatalf("expected a key press at %v, got %d at %v", h.Time, presses, pressedAt)
+	}
+	h.Wait(400 * time.Millisecond)
+	if repeats != 0 {
+		t.Errorf("expected no repeats before 500ms, got %d", repeats)
+	}
+	h.Wait(200 * time.Millisecond)
+	if repeats == 0 {
+		t.Error("expected the key to repeat after 500ms")
+	}
+	h.KeyUp(ebiten.KeyA)
+}
@@ -0,0 +1,265 @@
+package rebui
+
+import (
+	"fmt"
+	"strings"
+)
+
+// layoutStepKind is a single part of laying out a node.
+type layoutStepKind int
+
+const (
+	stepSize layoutStepKind = iota
+	stepX
+	stepY
+	stepArrange // Positions the children of a ContainerWidget.
+)
+
+func (k layoutStepKind) String() string {
+	switch k {
+	case stepX:
+		return "X"
+	case stepY:
+		return "Y"
+	case stepArrange:
+		return "children"
+	}
+	return "size"
+}
+
+// layoutStep is a vertex within the layout dependency graph.
+type layoutStep struct {
+	node   *Node
+	parent *Node
+	kind   layoutStepKind
+}
+
+type layoutStepKey struct {
+	node *Node
+	kind layoutStepKind
+}
+
+// layoutDependency is an edge within the layout dependency graph, from a step to the step it requires.
+type layoutDependency struct {
+	on    layoutStepKey
+	field string // The field that caused the dependency, used for error reporting.
+}
+
+// layoutGraph contains every layout step along with the steps it depends upon.
+type layoutGraph struct {
+	steps        []layoutStep
+	dependencies map[layoutStepKey][]layoutDependency
+	parents      map[*Node]*Node
+}
+
+func (g *layoutGraph) depend(from layoutStepKey, on layoutStepKey, field string) {
+	if from == on {
+		return
+	}
+	g.dependencies[from] = append(g.dependencies[from], layoutDependency{on: on, field: field})
+}
+
+// arranger returns the node's parent if it is a container that arranges the node.
+func (g *layoutGraph) arranger(n *Node) *Node {
+	if p := g.parents[n]; p != nil && p.isContainer() {
+		return p
+	}
+	return nil
+}
+
+// sizeOf returns the step that finalizes the node's size. For arranged nodes this is their container's arrange step, as containers may grow or shrink their children. However, siblings within the same container receive the node's own size step, as they are sized before being arranged.
+func (g *layoutGraph) sizeOf(n *Node, from *Node) layoutStepKey {
+	if p := g.arranger(n); p != nil && g.parents[from] != p {
+		return layoutStepKey{p, stepArrange}
+	}
+	return layoutStepKey{n, stepSize}
+}
+
+// positionOf returns the step that finalizes the node's position on the given axis.
+func (g *layoutGraph) positionOf(n *Node, axis layoutStepKind) layoutStepKey {
+	if p := g.arranger(n); p != nil {
+		return layoutStepKey{p, stepArrange}
+	}
+	return layoutStepKey{n, axis}
+}
+
+// dependOnRefs adds dependencies for every node referenced by the given position field. A referenced node's size is always required, but its position is only required if the reference is positional, such as "after" as opposed to "of".
+func (g *layoutGraph) dependOnRefs(l *Layout, from layoutStepKey, field, value string, vertical bool) {
+	axis := stepX
+	if vertical {
+		axis = stepY
+	}
+	for _, ref := range l.positionRefs(value, vertical) {
+		g.depend(from, g.sizeOf(ref.node, from.node), field)
+		if ref.positional {
+			g.depend(from, g.positionOf(ref.node, axis), field)
+		}
+	}
+}
+
+// dependOnTrackRefs adds dependencies for every node referenced by the tracks of the given track definition.
+func (g *layoutGraph) dependOnTrackRefs(l *Layout, from layoutStepKey, field, value string, vertical bool) {
+	for _, t := range splitTracks(value) {
+		if _, ok := parseFraction(t); !ok {
+			g.dependOnRefs(l, from, field, t, vertical)
+		}
+	}
+}
+
+func (l *Layout) buildLayoutGraph(ns Nodes) *layoutGraph {
+	g := &layoutGraph{
+		dependencies: make(map[layoutStepKey][]layoutDependency),
+		parents:      make(map[*Node]*Node),
+	}
+
+	var walk func(ns Nodes, parent *Node)
+	walk = func(ns Nodes, parent *Node) {
+		for _, n := range ns {
+			g.parents[n] = parent
+			walk(n.Children, n)
+		}
+	}
+	walk(ns, nil)
+
+	var add func(ns Nodes, parent *Node)
+	add = func(ns Nodes, parent *Node) {
+		for _, n := range ns {
+			size := layoutStepKey{n, stepSize}
+			g.steps = append(g.steps, layoutStep{n, parent, stepSize})
+
+			// Children are contained within their parent, so they require its size and position.
+			if parent != nil {
+				g.depend(size, g.sizeOf(parent, n), "Width")
+				// Children of cell containers are sized within their cell, which requires the container's tracks and gaps.
+				if _, ok := parent.Widget.(CellContainerWidget); ok {
+					g.dependOnTrackRefs(l, size, "Columns", parent.Columns, false)
+					g.dependOnTrackRefs(l, size, "Rows", parent.Rows, true)
+					g.dependOnRefs(l, size, "Gap", parent.Gap, false)
+					g.dependOnRefs(l, size, "Gap", parent.Gap, true)
+				}
+			}
+			g.dependOnRefs(l, size, "Width", n.Width, false)
+			g.dependOnRefs(l, size, "Height", n.Height, true)
+			g.dependOnRefs(l, size, "FontSize", n.FontSize, true)
+			g.dependOnRefs(l, size, "Padding", n.Padding, false)
+			g.dependOnRefs(l, size, "Padding", n.Padding, true)
+
+			// Arranged nodes are positioned by their container, so they have no position steps of their own.
+			if g.arranger(n) == nil {
+				x := layoutStepKey{n, stepX}
+				y := layoutStepKey{n, stepY}
+				g.steps = append(g.steps, layoutStep{n, parent, stepX}, layoutStep{n, parent, stepY})
+
+				// Our own position requires our size, as origins and some relations are based upon it.
+				g.depend(x, size, "X")
+				g.depend(y, size, "Y")
+				if parent != nil {
+					g.depend(x, g.positionOf(parent, stepX), "X")
+					g.depend(y, g.positionOf(parent, stepY), "Y")
+				}
+				g.dependOnRefs(l, x, "X", n.X, false)
+				g.dependOnRefs(l, x, "OriginX", n.OriginX, false)
+				g.dependOnRefs(l, y, "Y", n.Y, true)
+				g.dependOnRefs(l, y, "OriginY", n.OriginY, true)
+			}
+
+			// Containers arrange their children once they and their children are sized and they are positioned.
+			if n.isContainer() {
+				arrange := layoutStepKey{n, stepArrange}
+				g.steps = append(g.steps, layoutStep{n, parent, stepArrange})
+				g.depend(arrange, g.sizeOf(n, nil), "Width")
+				g.depend(arrange, g.positionOf(n, stepX), "X")
+				g.depend(arrange, g.positionOf(n, stepY), "Y")
+				for _, c := range n.Children {
+					g.depend(arrange, layoutStepKey{c, stepSize}, "Children")
+				}
+				g.dependOnRefs(l, arrange, "Gap", n.Gap, false)
+				g.dependOnRefs(l, arrange, "Gap", n.Gap, true)
+				g.dependOnTrackRefs(l, arrange, "Columns", n.Columns, false)
+				g.dependOnTrackRefs(l, arrange, "Rows", n.Rows, true)
+			}
+
+			add(n.Children, n)
+		}
+	}
+	add(ns, nil)
+
+	return g
+}
+
+// resolveOrder returns the layout steps for the given nodes and their children, ordered so that every step comes after the steps it depends upon. Steps without dependencies between them retain declaration order. Circular dependencies are reported as errors and broken at the point they are discovered.
+func (l *Layout) resolveOrder(ns Nodes) []layoutStep {
+	g := l.buildLayoutGraph(ns)
+
+	steps := make(map[layoutStepKey]layoutStep, len(g.steps))
+	for _, s := range g.steps {
+		steps[layoutStepKey{s.node, s.kind}] = s
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[layoutStepKey]int, len(g.steps))
+	order := make([]layoutStep, 0, len(g.steps))
+	var stack []layoutStepKey
+	var stackFields []string
+
+	var visit func(k layoutStepKey)
+	visit = func(k layoutStepKey) {
+		state[k] = visiting
+		stack = append(stack, k)
+		for _, dep := range g.dependencies[k] {
+			switch state[dep.on] {
+			case unvisited:
+				stackFields = append(stackFields, dep.field)
+				visit(dep.on)
+				stackFields = stackFields[:len(stackFields)-1]
+			case visiting:
+				l.reportCycle(stack, append(stackFields, dep.field), dep.on)
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[k] = visited
+		if s, ok := steps[k]; ok {
+			order = append(order, s)
+		}
+	}
+
+	for _, s := range g.steps {
+		k := layoutStepKey{s.node, s.kind}
+		if state[k] == unvisited {
+			visit(k)
+		}
+	}
+
+	return order
+}
+
+// reportCycle reports a circular dependency that ends at the given step.
+func (l *Layout) reportCycle(stack []layoutStepKey, fields []string, at layoutStepKey) {
+	start := 0
+	for i, k := range stack {
+		if k == at {
+			start = i
+			break
+		}
+	}
+	var path []string
+	for i := start; i < len(stack); i++ {
+		path = append(path, nodeName(stack[i].node)+"."+fields[i])
+	}
+	path = append(path, nodeName(at.node))
+
+	first := stack[start]
+	l.reportError(&l.layoutErrors, newNodeError(first.node, fields[start], fmt.Errorf("%w: %s", ErrCircularReference, strings.Join(path, " -> "))))
+}
+
+// nodeName returns the node's ID, or its type if it has no ID.
+func nodeName(n *Node) string {
+	if n.ID != "" {
+		return n.ID
+	}
+	return "<" + n.Type + ">"
+}
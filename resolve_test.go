@@ -0,0 +1,95 @@
+package rebui_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/kettek/rebui"
+	"github.com/kettek/rebui/rebuitest"
+)
+
+func TestResolveOutOfOrder(t *testing.T) {
+	// Each node refers to one declared after it, so they can only be laid out in reverse.
+	h := newCollectingHarness(t,
+		rebui.Node{Type: "Area", ID: "c", X: "after b", Y: "after b", Width: "30", Height: "10"},
+		rebui.Node{Type: "Area", ID: "b", X: "after a", Width: "50% of a", Height: "100% of a"},
+		rebui.Node{Type: "Area", ID: "a", X: "10", Y: "5", Width: "40", Height: "20"},
+	)
+	if errs := h.Layout.Errors(); len(errs) != 0 {
+		t.Fatalf("expected no errors, got %v", errs)
+	}
+	expected := map[string]rebuitest.Rect{
+		"a": {X: 10, Y: 5, Width: 40, Height: 20},
+		"b": {X: 50, Y: 0, Width: 20, Height: 20},
+		"c": {X: 70, Y: 20, Width: 30, Height: 10},
+	}
+	for id, r := range expected {
+		if got := h.Rect(id); got != r {
+			t.Errorf("%s: expected %+v, got %+v", id, r, got)
+		}
+	}
+}
+
+func TestResolveCycles(t *testing.T) {
+	tests := []struct {
+		name  string
+		nodes []rebui.Node
+		field string
+		path  string
+	}{
+		{
+			"positions",
+			[]rebui.Node{
+				{Type: "Area", ID: "a", X: "after b", Width: "10", Height: "10"},
+				{Type: "Area", ID: "b", X: "after a", Width: "10", Height: "10"},
+			},
+			"X",
+			"a.X -> b.X -> a",
+		},
+		{
+			"sizes",
+			[]rebui.Node{
+				{Type: "Area", ID: "a", Width: "50% of b", Height: "10"},
+				{Type: "Area", ID: "b", Width: "50% of a", Height: "10"},
+			},
+			"Width",
+			"a.Width -> b.Width -> a",
+		},
+		{
+			"three nodes",
+			[]rebui.Node{
+				{Type: "Area", ID: "a", Y: "after b", Width: "10", Height: "10"},
+				{Type: "Area", ID: "b", Y: "after c", Width: "10", Height: "10"},
+				{Type: "Area", ID: "c", Y: "after a", Width: "10", Height: "10"},
+			},
+			"Y",
+			"a.Y -> b.Y -> c.Y -> a",
+		},
+	}
+	for _, tt := range tests {
+		h := newCollectingHarness(t, tt.nodes...)
+		errs := h.Layout.Errors()
+		if len(errs) != 1 {
+			t.Errorf("%s: expected 1 error, got %v", tt.name, errs)
+			continue
+		}
+		var nerr *rebui.NodeError
+		if !errors.Is(errs[0], rebui.ErrCircularReference) || !errors.As(errs[0], &nerr) {
+			t.Errorf("%s: expected a NodeError wrapping %v, got %v", tt.name, rebui.ErrCircularReference, errs[0])
+			continue
+		}
+		if nerr.NodeID != "a" || nerr.Field != tt.field {
+			t.Errorf("%s: expected the error in the %s of a, got the %s of %s", tt.name, tt.field, nerr.Field, nerr.NodeID)
+		}
+		if !strings.Contains(errs[0].Error(), tt.path) {
+			t.Errorf("%s: expected the path %q, got %v", tt.name, tt.path, errs[0])
+		}
+		// The cycle is broken rather than left unresolved, so every node is still laid out.
+		for _, n := range tt.nodes {
+			if r := h.Rect(n.ID); r.Height != 10 {
+				t.Errorf("%s: expected %s to be laid out, got %+v", tt.name, n.ID, r)
+			}
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package rebui
+
+import (
+	"image"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/kettek/rebui/widgets/getters"
+)
+
+// view returns the offset applied to the node by any ScrollWidgets containing it, along with the screen area it is clipped to. If clipped is false, the node is not within any ScrollWidget.
+func (n *Node) view() (dx, dy float64, clip image.Rectangle, clipped bool) {
+	var ancestors []*Node
+	for p := n.Parent; p != nil; p = p.Parent {
+		ancestors = append(ancestors, p)
+	}
+	for i := len(ancestors) - 1; i >= 0; i-- {
+		a := ancestors[i]
+		sw, ok := a.Widget.(ScrollWidget)
+		if !ok {
+			continue
+		}
+		r := image.Rect(
+			int(math.Floor(a.x+dx)),
+			int(math.Floor(a.y+dy)),
+			int(math.Ceil(a.x+a.width+dx)),
+			int(math.Ceil(a.y+a.height+dy)),
+		)
+		if clipped {
+			clip = clip.Intersect(r)
+		} else {
+			clip = r
+			clipped = true
+		}
+		sx, sy := sw.ScrollOffset()
+		dx -= sx
+		dy -= sy
+	}
+	return
+}
+
+// hitNode checks if the given screen coordinate hits the node, accounting for the clipping and scrolling of any ScrollWidgets containing it. The coordinate is returned in the node's own space, which differs from screen space when scrolled.
+func (l *Layout) hitNode(n *Node, hit HitChecker, x, y float64) (float64, float64, bool) {
+	dx, dy, clip, clipped := n.view()
+	if clipped && (x < float64(clip.Min.X) || y < float64(clip.Min.Y) || x >= float64(clip.Max.X) || y >= float64(clip.Max.Y)) {
+		return x - dx, y - dy, false
+	}
+	x, y = x-dx, y-dy
+	return x, y, hit.Hit(x, y)
+}
+
+// relativePointer returns the given screen coordinate relative to the node's widget.
+func (n *Node) relativePointer(x, y float64) (rx, ry float64) {
+	dx, dy, _, _ := n.view()
+	if gx, ok := n.Widget.(getters.X); ok {
+		rx = x - dx - gx.GetX()
+	}
+	if gy, ok := n.Widget.(getters.Y); ok {
+		ry = y - dy - gy.GetY()
+	}
+	return
+}
+
+// drawTarget returns the image and options that the node's widget should draw with, clipped and offset by any ScrollWidgets containing it. If ok is false, the node is entirely clipped.
+func (l *Layout) drawTarget(n *Node) (target *ebiten.Image, op *ebiten.DrawImageOptions, ok bool) {
+	dx, dy, clip, clipped := n.view()
+	target = l.RenderTarget
+	if clipped {
+		clip = clip.Intersect(target.Bounds())
+		if clip.Empty() {
+			return nil, nil, false
+		}
+		target = target.SubImage(clip).(*ebiten.Image)
+	}
+
+	op = &ebiten.DrawImageOptions{}
+	if xg, ok := n.Widget.(getters.X); ok {
+		op.GeoM.Translate(xg.GetX(), 0)
+	} else {
+		op.GeoM.Translate(n.x, 0)
+	}
+	if yg, ok := n.Widget.(getters.Y); ok {
+		op.GeoM.Translate(0, yg.GetY())
+	} else {
+		op.GeoM.Translate(0, n.y)
+	}
+	op.GeoM.Translate(dx, dy)
+	return target, op, true
+}
+
+// layoutScrollContent assigns the size of the area covered by the children of each ScrollWidget. This must be called after layout.
+func (l *Layout) layoutScrollContent() {
+	l.Nodes.ForEach(func(n *Node) bool {
+		sw, ok := n.Widget.(ScrollWidget)
+		if !ok {
+			return false
+		}
+		right, bottom := n.width, n.height
+		var extend func(ns Nodes)
+		extend = func(ns Nodes) {
+			for _, c := range ns {
+				if c.Hidden {
+					continue
+				}
+				right = max(right, c.x+c.width-n.x+n.paddingX)
+				bottom = max(bottom, c.y+c.height-n.y+n.paddingY)
+				// The children of nested ScrollWidgets are clipped to them, so they cannot extend our content.
+				if _, ok := c.Widget.(ScrollWidget); !ok {
+					extend(c.Children)
+				}
+			}
+		}
+		extend(n.Children)
+		sw.AssignContentSize(right, bottom)
+		return false
+	})
+}
@@ -0,0 +1,282 @@
+package rebui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/kettek/rebui/events"
+	"github.com/kettek/rebui/widgets/receivers"
+)
+
+// Chord is a key along with the modifier keys that must be held with it.
+type Chord struct {
+	Key ebiten.Key
+	events.Modifiers
+}
+
+// ParseChord parses a chord such as "Ctrl+S", "Ctrl+Shift+Z", or "Escape". Modifiers are "Shift", "Ctrl" (or "Control"), "Alt" (or "Option"), and "Meta" (or "Cmd" or "Super"), and keys use Ebitengine's key names. Names are case-insensitive.
+func ParseChord(s string) (c Chord, err error) {
+	parts := strings.Split(s, "+")
+	for i, part := range parts {
+		part = strings.TrimSpace(part)
+		if i == len(parts)-1 {
+			if err := c.Key.UnmarshalText([]byte(part)); err != nil {
+				return c, fmt.Errorf("%w %q: unknown key %q", ErrBadShortcut, s, part)
+			}
+			break
+		}
+		switch strings.ToLower(part) {
+		case "shift":
+			c.Shift = true
+		case "ctrl", "control":
+			c.Ctrl = true
+		case "alt", "option":
+			c.Alt = true
+		case "meta", "cmd", "super":
+			c.Meta = true
+		default:
+			return c, fmt.Errorf("%w %q: unknown modifier %q", ErrBadShortcut, s, part)
+		}
+	}
+	return c, nil
+}
+
+// String returns the chord in the form accepted by ParseChord.
+func (c Chord) String() string {
+	var parts []string
+	if c.Ctrl {
+		parts = append(parts, "Ctrl")
+	}
+	if c.Alt {
+		parts = append(parts, "Alt")
+	}
+	if c.Shift {
+		parts = append(parts, "Shift")
+	}
+	if c.Meta {
+		parts = append(parts, "Meta")
+	}
+	return strings.Join(append(parts, c.Key.String()), "+")
+}
+
+// matches returns if the key press is of the chord, with exactly the chord's modifiers held.
+func (c Chord) matches(evt *events.KeyPress) bool {
+	return evt.Key == c.Key && evt.Modifiers == c.Modifiers
+}
+
+// editsText returns if the chord's key press may be used to type or edit text, which is the case for keys held with no modifier or only Shift, other than Escape and the function keys.
+func (c Chord) editsText() bool {
+	if c.Ctrl || c.Alt || c.Meta {
+		return false
+	}
+	return c.Key != ebiten.KeyEscape && (c.Key < ebiten.KeyF1 || c.Key > ebiten.KeyF24)
+}
+
+// ShortcutScope determines when a shortcut is active.
+type ShortcutScope int
+
+// Our shortcut scopes.
+const (
+	ShortcutGlobal  ShortcutScope = iota // The shortcut is always active.
+	ShortcutVisible                      // The shortcut is active while its node and all of its parents are visible.
+	ShortcutFocused                      // The shortcut is active while its node or one of its children is focused.
+)
+
+// Shortcut is a key chord bound to a handler.
+type Shortcut struct {
+	Chord   Chord
+	Scope   ShortcutScope
+	Node    *Node // The node the scope applies to. This is nil for global shortcuts.
+	Handler func(EventKeyPress)
+}
+
+// BindShortcut binds the chord to a handler that is always active, returning ErrShortcutConflict if the chord is already bound globally. The chord may also be bound to nodes, which take precedence over it while active.
+func (l *Layout) BindShortcut(chord string, handler func(EventKeyPress)) (*Shortcut, error) {
+	return l.bindShortcut(chord, ShortcutGlobal, nil, handler)
+}
+
+// BindVisibleShortcut binds the chord to a handler that is active while the node and all of its parents are visible, returning ErrShortcutConflict if the chord is already bound in the same way to the node. It takes precedence over global shortcuts and visible shortcuts on the node's parents.
+func (l *Layout) BindVisibleShortcut(n *Node, chord string, handler func(EventKeyPress)) (*Shortcut, error) {
+	return l.bindShortcut(chord, ShortcutVisible, n, handler)
+}
+
+// BindFocusedShortcut binds the chord to a handler that is active while the node or one of its children is focused, returning ErrShortcutConflict if the chord is already bound in the same way to the node. It takes precedence over global and visible shortcuts and focused shortcuts on the node's parents.
+func (l *Layout) BindFocusedShortcut(n *Node, chord string, handler func(EventKeyPress)) (*Shortcut, error) {
+	return l.bindShortcut(chord, ShortcutFocused, n, handler)
+}
+
+func (l *Layout) bindShortcut(chord string, scope ShortcutScope, n *Node, handler func(EventKeyPress)) (*Shortcut, error) {
+	c, err := ParseChord(chord)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range l.shortcuts {
+		if s.Chord == c && s.Scope == scope && s.Node == n {
+			return nil, fmt.Errorf("%w: %q is already bound", ErrShortcutConflict, c)
+		}
+	}
+	s := &Shortcut{
+		Chord:   c,
+		Scope:   scope,
+		Node:    n,
+		Handler: handler,
+	}
+	l.shortcuts = append(l.shortcuts, s)
+	return s, nil
+}
+
+// UnbindShortcut removes the shortcut.
+func (l *Layout) UnbindShortcut(s *Shortcut) {
+	l.shortcuts = slices.DeleteFunc(l.shortcuts, func(s2 *Shortcut) bool {
+		return s2 == s
+	})
+}
+
+// isVisible returns if the node and all of its parents are not hidden.
+func (n *Node) isVisible() bool {
+	for ; n != nil; n = n.Parent {
+		if n.Hidden {
+			return false
+		}
+	}
+	return true
+}
+
+// depth returns how many parents the node has.
+func (n *Node) depth() (d int) {
+	for n = n.Parent; n != nil; n = n.Parent {
+		d++
+	}
+	return
+}
+
+// shortcutPriority returns how specific an active shortcut is, or -1 if it is inactive. Focused shortcuts nearest the focused node come first, followed by visible shortcuts on the deepest nodes and then global shortcuts.
+func (l *Layout) shortcutPriority(s *Shortcut) int {
+	const scopeWeight = 1 << 16 // Greater than any node depth, so that scopes never overlap.
+	switch s.Scope {
+	case ShortcutFocused:
+		d := 0
+		for n := l.focusedNode; n != nil; n = n.Parent {
+			if n == s.Node {
+				return scopeWeight*2 - d
+			}
+			d++
+		}
+	case ShortcutVisible:
+		if s.Node.isVisible() {
+			return scopeWeight + s.Node.depth()
+		}
+	case ShortcutGlobal:
+		return 0
+	}
+	return -1
+}
+
+// typing returns if the focused node receives typed text, such as a TextInput.
+func (l *Layout) typing() bool {
+	if l.focusedNode == nil {
+		return false
+	}
+	_, ok := l.focusedNode.Widget.(receivers.KeyInput)
+	return ok || l.focusedNode.OnKeyInput != nil
+}
+
+// processShortcut calls the handler of the most specific active shortcut matching the key press, if any. Among equally specific shortcuts, the most recently bound is used. The key press's default action is prevented if a shortcut is found. While the focused node receives typed text, shortcuts whose keys may type or edit text are skipped, so that they go to the focused node instead.
+func (l *Layout) processShortcut(evt *events.KeyPress) {
+	var best *Shortcut
+	bestPriority := -1
+	typing := l.typing()
+	for _, s := range l.shortcuts {
+		if !s.Chord.matches(evt) || (typing && s.Chord.editsText()) {
+			continue
+		}
+		if p := l.shortcutPriority(s); p >= 0 && p >= bestPriority {
+			best, bestPriority = s, p
+		}
+	}
+	if best == nil {
+		return
+	}
+	evt.PreventDefault()
+	if best.Handler != nil {
+		best.Handler(evt)
+	}
+}
+
+// bindNodeShortcut binds the node's Shortcut field, if set, to activate the node while it is visible.
+func (l *Layout) bindNodeShortcut(n *Node) {
+	if n.Shortcut == "" {
+		return
+	}
+	if _, err := l.BindVisibleShortcut(n, n.Shortcut, func(EventKeyPress) {
+		l.ActivateNode(n)
+	}); err != nil {
+		l.reportError(&l.generateErrors, newNodeError(n, "Shortcut", err))
+	}
+}
+
+// validateShortcuts checks the Shortcut field of every node for bad chords, and checks for chords bound more than once where the bindings can be active at the same time, whether bound by a node's Shortcut field or by BindShortcut, BindVisibleShortcut, or BindFocusedShortcut.
+func (l *Layout) validateShortcuts() (errs []error) {
+	bound := make(map[Chord]*Node)
+	// Parents may not be assigned until generation, so visibility is passed down instead.
+	var validate func(ns Nodes, hidden bool)
+	validate = func(ns Nodes, hidden bool) {
+		for _, n := range ns {
+			if n.Shortcut != "" {
+				c, err := ParseChord(n.Shortcut)
+				if err != nil {
+					errs = append(errs, newNodeError(n, "Shortcut", err))
+				} else if !hidden && !n.Hidden && n.Widget == nil { // Generated nodes have their shortcuts bound, which are checked below.
+					if other, ok := bound[c]; ok {
+						errs = append(errs, newNodeError(n, "Shortcut", fmt.Errorf("%w: %q is also bound to node %q", ErrShortcutConflict, c, other.ID)))
+					} else {
+						bound[c] = n
+					}
+				}
+			}
+			validate(n.Children, hidden || n.Hidden)
+		}
+	}
+	validate(l.Nodes, false)
+
+	for i, s := range l.shortcuts {
+		for _, other := range l.shortcuts[:i] {
+			if s.Chord != other.Chord || !s.overlaps(other) {
+				continue
+			}
+			n := s.Node
+			if n == nil { // Global shortcuts never conflict with each other, so the other has a node.
+				n = other.Node
+			}
+			errs = append(errs, newNodeError(n, "Shortcut", fmt.Errorf("%w: %q is bound %s and %s", ErrShortcutConflict, s.Chord, other.describe(), s.describe())))
+		}
+	}
+	return
+}
+
+// overlaps returns if the shortcuts can be active at the same time. Visible and focused shortcuts on nodes that are not currently visible are not considered active.
+func (s *Shortcut) overlaps(other *Shortcut) bool {
+	for _, s2 := range []*Shortcut{s, other} {
+		if s2.Scope != ShortcutGlobal && !s2.Node.isVisible() {
+			return false
+		}
+	}
+	// Focused shortcuts are only active together if one's node contains the other's.
+	if s.Scope == ShortcutFocused && other.Scope == ShortcutFocused {
+		return s.Node.isAncestorOf(other.Node) || other.Node.isAncestorOf(s.Node)
+	}
+	return true
+}
+
+// describe returns when the shortcut is active, for use in errors.
+func (s *Shortcut) describe() string {
+	switch s.Scope {
+	case ShortcutVisible:
+		return fmt.Sprintf("while node %q is visible", s.Node.ID)
+	case ShortcutFocused:
+		return fmt.Sprintf("while node %q is focused", s.Node.ID)
+	}
+	return "globally"
+}
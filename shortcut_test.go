@@ -0,0 +1,190 @@
+package rebui_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/kettek/rebui"
+	_ "github.com/kettek/rebui/defaults/font"
+	"github.com/kettek/rebui/rebuitest"
+	_ "github.com/kettek/rebui/widgets"
+)
+
+func TestShortcutsWhileTyping(t *testing.T) {
+	h := rebuitest.New(t, 320, 240,
+		rebui.Node{
+			Type:       "TextInput",
+			ID:         "input",
+			Width:      "200",
+			Height:     "20",
+			FocusIndex: 1,
+		},
+		rebui.Node{
+			Type:       "Button",
+			ID:         "button",
+			Y:          "after input",
+			Width:      "200",
+			Height:     "20",
+			FocusIndex: 2,
+		},
+	)
+
+	var fired []string
+	for _, chord := range []string{"S", "Shift+S", "Ctrl+S", "Escape", "F2"} {
+		if _, err := h.Layout.BindShortcut(chord, func(rebui.EventKeyPress) {
+			fired = append(fired, chord)
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expect := func(when string, chords ...string) {
+		t.Helper()
+		if len(fired) != len(chords) {
+			t.Errorf("%s: expected %v to fire, got %v", when, chords, fired)
+		} else {
+			for i := range chords {
+				if fired[i] != chords[i] {
+					t.Errorf("%s: expected %v to fire, got %v", when, chords, fired)
+					break
+				}
+			}
+		}
+		fired = nil
+	}
+	press := func() {
+		h.PressKey(ebiten.KeyS)
+		h.PressKeys(ebiten.KeyShift, ebiten.KeyS)
+		h.PressKeys(ebiten.KeyControl, ebiten.KeyS)
+		h.PressKey(ebiten.KeyEscape)
+		h.PressKey(ebiten.KeyF2)
+	}
+
+	h.Layout.Focus(h.Node("input"))
+	press()
+	expect("while typing", "Ctrl+S", "Escape", "F2")
+
+	h.Layout.Focus(h.Node("button"))
+	press()
+	expect("while a button is focused", "S", "Shift+S", "Ctrl+S", "Escape", "F2")
+
+	h.Layout.Blur()
+	press()
+	expect("while nothing is focused", "S", "Shift+S", "Ctrl+S", "Escape", "F2")
+}
+
+func TestShortcutsWhileTypingInNode(t *testing.T) {
+	h := rebuitest.New(t, 320, 240, rebui.Node{
+		Type:       "Area",
+		ID:         "area",
+		Width:      "200",
+		Height:     "20",
+		FocusIndex: 1,
+	})
+
+	fired := 0
+	if _, err := h.Layout.BindShortcut("S", func(rebui.EventKeyPress) {
+		fired++
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	h.Layout.Focus(h.Node("area"))
+	h.PressKey(ebiten.KeyS)
+	if fired != 1 {
+		t.Fatalf("expected the shortcut to fire once, fired %d times", fired)
+	}
+
+	// A node that handles typed text itself is treated the same as a text widget.
+	h.Node("area").OnKeyInput = func(rebui.EventKeyInput) {}
+	h.PressKey(ebiten.KeyS)
+	if fired != 1 {
+		t.Errorf("expected the shortcut not to fire while typing, fired %d times", fired)
+	}
+}
+
+func TestValidateShortcutConflicts(t *testing.T) {
+	h := rebuitest.New(t, 320, 240,
+		rebui.Node{
+			Type:     "Button",
+			ID:       "save",
+			Width:    "50",
+			Height:   "20",
+			Shortcut: "Ctrl+S",
+		},
+		rebui.Node{
+			Type:   "Area",
+			ID:     "editor",
+			Y:      "after save",
+			Width:  "200",
+			Height: "100",
+			Children: rebui.Nodes{
+				{
+					Type:   "Area",
+					ID:     "line",
+					Width:  "200",
+					Height: "20",
+				},
+			},
+		},
+		rebui.Node{
+			Type:   "Area",
+			ID:     "sidebar",
+			X:      "after editor",
+			Width:  "100",
+			Height: "100",
+		},
+		rebui.Node{
+			Type:   "Area",
+			ID:     "dialog",
+			Width:  "100",
+			Height: "100",
+			Hidden: true,
+		},
+	)
+
+	noop := func(rebui.EventKeyPress) {}
+	bindings := []struct {
+		scope rebui.ShortcutScope
+		node  string
+		chord string
+	}{
+		{rebui.ShortcutGlobal, "", "Ctrl+S"},
+		{rebui.ShortcutFocused, "editor", "Ctrl+S"},
+		// Hidden nodes' shortcuts cannot be active.
+		{rebui.ShortcutVisible, "dialog", "Ctrl+S"},
+		// Focused shortcuts are only active together when one node contains the other.
+		{rebui.ShortcutFocused, "editor", "Ctrl+O"},
+		{rebui.ShortcutFocused, "sidebar", "Ctrl+O"},
+		{rebui.ShortcutFocused, "line", "Ctrl+O"},
+	}
+	for _, b := range bindings {
+		var err error
+		switch b.scope {
+		case rebui.ShortcutGlobal:
+			_, err = h.Layout.BindShortcut(b.chord, noop)
+		case rebui.ShortcutVisible:
+			_, err = h.Layout.BindVisibleShortcut(h.Node(b.node), b.chord, noop)
+		case rebui.ShortcutFocused:
+			_, err = h.Layout.BindFocusedShortcut(h.Node(b.node), b.chord, noop)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := h.Layout.BindShortcut("Ctrl+S", noop); !errors.Is(err, rebui.ErrShortcutConflict) {
+		t.Errorf("expected binding the same global chord twice to conflict, got %v", err)
+	}
+
+	// Ctrl+S conflicts between the save button's field, the global binding, and the editor, and Ctrl+O between the editor and its line.
+	errs := h.Layout.Validate()
+	if len(errs) != 4 {
+		t.Fatalf("expected 4 conflicts, got %d: %v", len(errs), errs)
+	}
+	for _, err := range errs {
+		if !errors.Is(err, rebui.ErrShortcutConflict) {
+			t.Errorf("expected a shortcut conflict, got %v", err)
+		}
+	}
+}
@@ -1,5 +1,7 @@
 package rebui
 
+import "github.com/kettek/rebui/events"
+
 type currentState struct {
 	hoveredNodes []*Node
 	pressedNodes []*pressedNode
@@ -27,29 +29,29 @@ func (s *currentState) removeHovered(n *Node) {
 	}
 }
 
-func (s *currentState) isPressed(n *Node, id int) bool {
+func (s *currentState) isPressed(n *Node, id events.PointerID) bool {
 	for _, pn := range s.pressedNodes {
-		if pn.node == n && (id == -1 || pn.id == id) {
+		if pn.node == n && pn.id == id {
 			return true
 		}
 	}
 	return false
 }
 
-func (s *currentState) addPressed(n *Node, id int) {
+func (s *currentState) addPressed(n *Node, id events.PointerID) {
 	s.pressedNodes = append(s.pressedNodes, &pressedNode{n, id})
 }
 
-func (s *currentState) removePressed(n *Node, id int) {
+func (s *currentState) removePressed(n *Node, id events.PointerID) {
 	for i, pn := range s.pressedNodes {
-		if pn.node == n && (id == -1 || pn.id == id) {
+		if pn.node == n && pn.id == id {
 			s.pressedNodes = append(s.pressedNodes[:i], s.pressedNodes[i+1:]...)
 			return
 		}
 	}
 }
 
-func (s *currentState) removePressedID(id int) {
+func (s *currentState) removePressedID(id events.PointerID) {
 	for i := len(s.pressedNodes) - 1; i >= 0; i-- {
 		pn := s.pressedNodes[i]
 		if pn.id == id {
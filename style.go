@@ -13,6 +13,10 @@ const (
 	AlignTop    = style.Top
 	AlignMiddle = style.Middle
 	AlignBottom = style.Bottom
+	// Container-only alignments.
+	AlignStretch      = style.Stretch
+	AlignSpaceBetween = style.SpaceBetween
+	AlignSpaceAround  = style.SpaceAround
 )
 
 // Wrap is a type alias for style.Wrap.
@@ -35,3 +39,23 @@ const (
 	ImageStretchCover   = style.Cover
 	ImageStretchNearest = style.Nearest
 )
+
+// Cursor is a type alias for style.Cursor.
+type Cursor = style.Cursor
+
+// Our cursor types. See style package for more info.
+const (
+	CursorDefault    = style.CursorDefault
+	CursorText       = style.CursorText
+	CursorPointer    = style.CursorPointer
+	CursorCrosshair  = style.CursorCrosshair
+	CursorEWResize   = style.CursorEWResize
+	CursorNSResize   = style.CursorNSResize
+	CursorNESWResize = style.CursorNESWResize
+	CursorNWSEResize = style.CursorNWSEResize
+	CursorMove       = style.CursorMove
+	CursorNotAllowed = style.CursorNotAllowed
+)
+
+// Track is a type alias for style.Track.
+type Track = style.Track
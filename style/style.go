@@ -11,6 +11,12 @@ const (
 	Top    Alignment = "top"
 	Middle Alignment = "middle"
 	Bottom Alignment = "bottom"
+	// Stretch fills the available space. This is only used for the cross axis of containers.
+	Stretch Alignment = "stretch"
+	// SpaceBetween distributes free space between items. This is only used for the main axis of containers.
+	SpaceBetween Alignment = "space-between"
+	// SpaceAround distributes free space around items. This is only used for the main axis of containers.
+	SpaceAround Alignment = "space-around"
 )
 
 // Wrap is used to determine how text is word wrapped.
@@ -37,3 +43,28 @@ const (
 	// Nearest works like Cover, but to nearest whole multiple.
 	Nearest ImageStretch = "nearest"
 )
+
+// Cursor is the shape of the mouse cursor.
+type Cursor string
+
+// Our various cursors.
+const (
+	CursorDefault    Cursor = "default"
+	CursorText       Cursor = "text"
+	CursorPointer    Cursor = "pointer"
+	CursorCrosshair  Cursor = "crosshair"
+	CursorEWResize   Cursor = "ew-resize"
+	CursorNSResize   Cursor = "ns-resize"
+	CursorNESWResize Cursor = "nesw-resize"
+	CursorNWSEResize Cursor = "nwse-resize"
+	CursorMove       Cursor = "move"
+	CursorNotAllowed Cursor = "not-allowed"
+)
+
+// Track is the size of a row or column within a grid.
+type Track struct {
+	// Size is the fixed size of the track in pixels.
+	Size float64
+	// Fraction is the track's share of the space left over once fixed tracks and gaps are subtracted. If it is non-zero, Size is ignored.
+	Fraction float64
+}
@@ -9,6 +9,18 @@ const (
 	relationAfter
 	relationAt
 	relationOf
+	relationBefore
+)
+
+// Anchors that may precede "of" or "at" to select an edge of the target node or to align against it.
+const (
+	anchorLeft   = "left"
+	anchorRight  = "right"
+	anchorTop    = "top"
+	anchorBottom = "bottom"
+	anchorCenter = "center"
+	anchorStart  = "start"
+	anchorEnd    = "end"
 )
 
 const (
@@ -21,13 +33,23 @@ const (
 const (
 	tUnit = iota + 1
 	tRelation
+	tOperator
+)
+
+// Functions that may be used within position expressions.
+const (
+	funcCalc  = "calc"
+	funcMin   = "min"
+	funcMax   = "max"
+	funcClamp = "clamp"
 )
 
 var tokenParser *tokenizer.Tokenizer
 
 func init() {
 	tokenParser = tokenizer.New()
-	tokenParser.DefineTokens(tRelation, []string{"after", "at", "of"})
+	tokenParser.DefineTokens(tRelation, []string{"after", "at", "of", "before"})
 	tokenParser.DefineTokens(tUnit, []string{"%", "vw", "vh"})
+	tokenParser.DefineTokens(tOperator, []string{"+", "-", "*", "/", "(", ")", ","})
 	tokenParser.AllowKeywordSymbols(tokenizer.Underscore, tokenizer.Numbers)
 }
@@ -0,0 +1,57 @@
+package rebui
+
+import "fmt"
+
+// Validate checks all Nodes, including hidden ones, for unknown types and cursors, duplicate IDs, and position fields with bad syntax, bad units, or references to IDs that do not exist. As template children are only created during generation, Validate should be called after Generate if templates are used.
+func (l *Layout) Validate() []error {
+	var errs []error
+	ids := make(map[string]bool)
+
+	var validate func(ns Nodes)
+	validate = func(ns Nodes) {
+		for _, n := range ns {
+			if n.ID != "" {
+				if ids[n.ID] {
+					errs = append(errs, newNodeError(n, "ID", fmt.Errorf("%w %q", ErrDuplicateID, n.ID)))
+				}
+				ids[n.ID] = true
+			}
+			if _, ok := handlers[n.Type]; !ok && n.Type != "" {
+				errs = append(errs, newNodeError(n, "Type", fmt.Errorf("%w %q", ErrUnknownNodeType, n.Type)))
+			}
+			for _, f := range []struct {
+				name     string
+				value    string
+				vertical bool
+			}{
+				{"X", n.X, false},
+				{"Y", n.Y, true},
+				{"Width", n.Width, false},
+				{"Height", n.Height, true},
+				{"OriginX", n.OriginX, false},
+				{"OriginY", n.OriginY, true},
+				{"FontSize", n.FontSize, true},
+				{"Padding", n.Padding, false},
+				{"Gap", n.Gap, false},
+			} {
+				if err := l.validatePosition(f.value, f.vertical); err != nil {
+					errs = append(errs, newNodeError(n, f.name, err))
+				}
+			}
+			if _, ok := cursorShapes[n.Cursor]; !ok && n.Cursor != "" {
+				errs = append(errs, newNodeError(n, "Cursor", fmt.Errorf("%w %q", ErrUnknownCursor, n.Cursor)))
+			}
+			if err := l.validateTracks(n.Columns, false); err != nil {
+				errs = append(errs, newNodeError(n, "Columns", err))
+			}
+			if err := l.validateTracks(n.Rows, true); err != nil {
+				errs = append(errs, newNodeError(n, "Rows", err))
+			}
+			validate(n.Children)
+		}
+	}
+	validate(l.Nodes)
+	errs = append(errs, l.validateShortcuts()...)
+
+	return errs
+}
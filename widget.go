@@ -18,6 +18,41 @@ type LayoutWidget interface {
 	Layout(width, height float64) (float64, float64)
 }
 
+// ContainerWidget is an optional interface that widgets can implement to position their Node's children, such as in a row or a grid.
+type ContainerWidget interface {
+	Widget
+	Arrange(ctx LayoutContext, children []*Arrangement)
+}
+
+// CellContainerWidget is an optional interface that ContainerWidgets can implement to give each child its own cell, such as in a grid. Children are then sized within their cell rather than the container's whole content area, so percentages are relative to the cell. Cells must return one context per child.
+type CellContainerWidget interface {
+	ContainerWidget
+	Cells(ctx LayoutContext, children []*Node) []LayoutContext
+}
+
+// ScrollWidget is an optional interface that widgets can implement to clip their Node's children to the Node's bounds and offset them by a scroll position. Children are laid out as usual, with the offset only applied when drawing and hit-testing, so scrolling does not require a relayout.
+type ScrollWidget interface {
+	Widget
+	// ScrollOffset returns how far the children are scrolled.
+	ScrollOffset() (x, y float64)
+	// ScrollBy scrolls by the given distance, returning if the offset changed.
+	ScrollBy(dx, dy float64) bool
+	// AssignContentSize is called after layout with the size of the area covered by the children, which is at least the Node's own size.
+	AssignContentSize(width, height float64)
+}
+
+// OverlayWidget is an optional interface that widgets can implement to draw over their Node's children, such as scrollbars. Overlays are drawn after all Nodes.
+type OverlayWidget interface {
+	Widget
+	DrawOverlay(*ebiten.Image, *ebiten.DrawImageOptions)
+}
+
+// Arrangement is the placement of a child within a ContainerWidget. Width and Height begin as the child's laid out size, and X and Y begin at the container's content origin. The container may change any of them.
+type Arrangement struct {
+	Node                *Node
+	X, Y, Width, Height float64
+}
+
 // AssignerBackgroundColor is an alias.
 type AssignerBackgroundColor = assigners.BackgroundColor
 
@@ -75,18 +110,75 @@ type AssignerWidth = assigners.Width
 // AssignerHeight is an alias.
 type AssignerHeight = assigners.Height
 
+// AssignerGap is an alias.
+type AssignerGap = assigners.Gap
+
+// AssignerTracks is an alias.
+type AssignerTracks = assigners.Tracks
+
 // AssignerDisable is an alias.
 type AssignerDisable = assigners.Disable
 
 // GetterDisabled is an alias.
 type GetterDisabled = getters.Disabled
 
+// GetterCursor is an alias.
+type GetterCursor = getters.Cursor
+
 // ReceiverPointerMove is an alias.
 type ReceiverPointerMove = receivers.PointerMove
 
 // ReceiverPointerGlobalMove is an alias.
 type ReceiverPointerGlobalMove = receivers.PointerGlobalMove
 
+// ReceiverPointerWheel is an alias.
+type ReceiverPointerWheel = receivers.PointerWheel
+
+// ReceiverPointerTap is an alias.
+type ReceiverPointerTap = receivers.PointerTap
+
+// ReceiverPointerDoubleClick is an alias.
+type ReceiverPointerDoubleClick = receivers.PointerDoubleClick
+
+// ReceiverPointerLongPress is an alias.
+type ReceiverPointerLongPress = receivers.PointerLongPress
+
+// ReceiverPinch is an alias.
+type ReceiverPinch = receivers.Pinch
+
+// ReceiverRotate is an alias.
+type ReceiverRotate = receivers.Rotate
+
+// ReceiverPan is an alias.
+type ReceiverPan = receivers.Pan
+
+// ReceiverDragStart is an alias.
+type ReceiverDragStart = receivers.DragStart
+
+// ReceiverDragEnter is an alias.
+type ReceiverDragEnter = receivers.DragEnter
+
+// ReceiverDragOver is an alias.
+type ReceiverDragOver = receivers.DragOver
+
+// ReceiverDragLeave is an alias.
+type ReceiverDragLeave = receivers.DragLeave
+
+// ReceiverDrop is an alias.
+type ReceiverDrop = receivers.Drop
+
+// ReceiverDragEnd is an alias.
+type ReceiverDragEnd = receivers.DragEnd
+
+// ReceiverGotCapture is an alias.
+type ReceiverGotCapture = receivers.GotCapture
+
+// ReceiverLostCapture is an alias.
+type ReceiverLostCapture = receivers.LostCapture
+
+// ReceiverCapture is an alias.
+type ReceiverCapture = receivers.Capture
+
 // ReceiverPointerIn is an alias.
 type ReceiverPointerIn = receivers.PointerIn
 
@@ -105,6 +197,24 @@ type ReceiverGlobalRelease = receivers.PointerGlobalRelease
 // ReceiverPointerPressed is an alias.
 type ReceiverPointerPressed = receivers.PointerPressed
 
+// ReceiverGamepadButtonPress is an alias.
+type ReceiverGamepadButtonPress = receivers.GamepadButtonPress
+
+// ReceiverGamepadButtonRelease is an alias.
+type ReceiverGamepadButtonRelease = receivers.GamepadButtonRelease
+
+// ReceiverGamepadAxis is an alias.
+type ReceiverGamepadAxis = receivers.GamepadAxis
+
+// ReceiverActivate is an alias.
+type ReceiverActivate = receivers.Activate
+
+// ReceiverCancel is an alias.
+type ReceiverCancel = receivers.Cancel
+
+// ReceiverUpdate is an alias.
+type ReceiverUpdate = receivers.Update
+
 // ReceiverGenerate is an anlias.
 type ReceiverGenerate = receivers.Generate
 
@@ -107,3 +107,13 @@ type Width interface {
 type Height interface {
 	AssignHeight(float64)
 }
+
+// Tracks is used to set the column and row tracks of a grid element.
+type Tracks interface {
+	AssignTracks(columns, rows []style.Track)
+}
+
+// Gap is used to set the horizontal and vertical spacing between the children of the given element.
+type Gap interface {
+	AssignGap(x, y float64)
+}
@@ -0,0 +1,192 @@
+package widgets
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/kettek/rebui"
+)
+
+// flow is the shared implementation of the Row, Column, and Flow containers. The main axis is aligned by HorizontalAlign for rows and VerticalAlign for columns, with the other alignment used for the cross axis.
+type flow struct {
+	Basic
+	gapX, gapY float64
+	valign     rebui.Alignment
+	halign     rebui.Alignment
+}
+
+// AssignGap sets the spacing between children.
+func (f *flow) AssignGap(x, y float64) {
+	f.gapX = x
+	f.gapY = y
+}
+
+// AssignVerticalAlignment sets the vertical alignment of children.
+func (f *flow) AssignVerticalAlignment(align rebui.Alignment) {
+	f.valign = align
+}
+
+// AssignHorizontalAlignment sets the horizontal alignment of children.
+func (f *flow) AssignHorizontalAlignment(align rebui.Alignment) {
+	f.halign = align
+}
+
+func (f *flow) Draw(screen *ebiten.Image, sop *ebiten.DrawImageOptions) {
+	// NOP, same as Area.
+}
+
+// flowAxes provides access to an arrangement's values along the main and cross axes.
+type flowAxes struct {
+	pos, size, crossPos, crossSize *float64
+}
+
+func axesOf(a *rebui.Arrangement, vertical bool) flowAxes {
+	if vertical {
+		return flowAxes{&a.Y, &a.Height, &a.X, &a.Width}
+	}
+	return flowAxes{&a.X, &a.Width, &a.Y, &a.Height}
+}
+
+// alignmentFactor returns how far along free space the given alignment places an item, from 0 for start to 1 for end.
+func alignmentFactor(align rebui.Alignment) float64 {
+	switch align {
+	case rebui.AlignCenter, rebui.AlignMiddle:
+		return 0.5
+	case rebui.AlignRight, rebui.AlignBottom:
+		return 1
+	}
+	return 0
+}
+
+func (f *flow) arrange(ctx rebui.LayoutContext, children []*rebui.Arrangement, vertical, wrap bool) {
+	mainStart, mainSize := ctx.OuterX, ctx.OuterWidth
+	crossStart, crossSize := ctx.OuterY, ctx.OuterHeight
+	mainGap, crossGap := f.gapX, f.gapY
+	mainAlign, crossAlign := f.halign, f.valign
+	if vertical {
+		mainStart, mainSize, crossStart, crossSize = crossStart, crossSize, mainStart, mainSize
+		mainGap, crossGap = crossGap, mainGap
+		mainAlign, crossAlign = crossAlign, mainAlign
+	}
+
+	// Break children into lines. Without wrapping, there is only ever one line.
+	var lines [][]*rebui.Arrangement
+	var line []*rebui.Arrangement
+	var used float64
+	for _, c := range children {
+		size := *axesOf(c, vertical).size
+		if wrap && len(line) > 0 && used+mainGap+size > mainSize {
+			lines = append(lines, line)
+			line = nil
+			used = 0
+		}
+		if len(line) > 0 {
+			used += mainGap
+		}
+		used += size
+		line = append(line, c)
+	}
+	if len(line) > 0 {
+		lines = append(lines, line)
+	}
+
+	for _, line := range lines {
+		lineCross := crossSize
+		if wrap {
+			lineCross = 0
+			for _, c := range line {
+				lineCross = max(lineCross, *axesOf(c, vertical).crossSize)
+			}
+		}
+		f.arrangeLine(line, vertical, mainStart, mainSize, mainGap, mainAlign)
+		for _, c := range line {
+			axes := axesOf(c, vertical)
+			if crossAlign == rebui.AlignStretch {
+				*axes.crossSize = lineCross
+			}
+			*axes.crossPos = crossStart + (lineCross-*axes.crossSize)*alignmentFactor(crossAlign)
+		}
+		crossStart += lineCross + crossGap
+	}
+}
+
+// arrangeLine grows or shrinks a line of children to fit, then positions them along the main axis.
+func (f *flow) arrangeLine(line []*rebui.Arrangement, vertical bool, start, size, gap float64, align rebui.Alignment) {
+	var total, growSum, shrinkSum float64
+	for _, c := range line {
+		total += *axesOf(c, vertical).size
+		growSum += c.Node.Grow
+		shrinkSum += c.Node.Shrink * *axesOf(c, vertical).size
+	}
+	total += gap * float64(len(line)-1)
+
+	free := size - total
+	if free > 0 && growSum > 0 {
+		for _, c := range line {
+			*axesOf(c, vertical).size += free * c.Node.Grow / growSum
+		}
+		free = 0
+	} else if free < 0 && shrinkSum > 0 {
+		for _, c := range line {
+			axes := axesOf(c, vertical)
+			*axes.size = max(0, *axes.size+free*c.Node.Shrink**axes.size/shrinkSum)
+		}
+		free = 0
+	}
+
+	offset, spacing := 0.0, gap
+	switch align {
+	case rebui.AlignSpaceBetween:
+		if len(line) > 1 && free > 0 {
+			spacing += free / float64(len(line)-1)
+		}
+	case rebui.AlignSpaceAround:
+		if free > 0 {
+			spacing += free / float64(len(line))
+			offset = free / float64(len(line)) / 2
+		}
+	default:
+		offset = free * alignmentFactor(align)
+	}
+
+	pos := start + offset
+	for _, c := range line {
+		axes := axesOf(c, vertical)
+		*axes.pos = pos
+		pos += *axes.size + spacing
+	}
+}
+
+// Row arranges its children horizontally in a single line.
+type Row struct {
+	flow
+}
+
+// Arrange positions the children left to right.
+func (r *Row) Arrange(ctx rebui.LayoutContext, children []*rebui.Arrangement) {
+	r.arrange(ctx, children, false, false)
+}
+
+// Column arranges its children vertically in a single line.
+type Column struct {
+	flow
+}
+
+// Arrange positions the children top to bottom.
+func (c *Column) Arrange(ctx rebui.LayoutContext, children []*rebui.Arrangement) {
+	c.arrange(ctx, children, true, false)
+}
+
+// Flow arranges its children horizontally, wrapping them onto new lines when they no longer fit.
+type Flow struct {
+	flow
+}
+
+// Arrange positions the children left to right and top to bottom.
+func (f *Flow) Arrange(ctx rebui.LayoutContext, children []*rebui.Arrangement) {
+	f.arrange(ctx, children, false, true)
+}
+
+func init() {
+	rebui.RegisterWidget("Row", &Row{})
+	rebui.RegisterWidget("Column", &Column{})
+	rebui.RegisterWidget("Flow", &Flow{})
+}
@@ -1,5 +1,7 @@
 package getters
 
+import "github.com/kettek/rebui/style"
+
 // Width is an interface for getting the width of an element. It is used during layout calculation to see if the user has changed the width of the element manually, and if not, to SetWidth.
 type Width interface {
 	GetWidth() float64
@@ -39,3 +41,8 @@ type BorderWidth interface {
 type Template interface {
 	IsTemplate()
 }
+
+// Cursor is an interface for getting the cursor an element requests while it is hovered. If it returns an empty cursor, the node's Cursor is used.
+type Cursor interface {
+	GetCursor() style.Cursor
+}
@@ -0,0 +1,168 @@
+package widgets
+
+import (
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/go-text/typesetting/segmenter"
+)
+
+// graphemeBoundaries returns the byte offsets of the grapheme cluster boundaries in s, from 0 through len(s). A grapheme cluster is what a user sees as a single character, such as "é" written as "e" and a combining accent, or an emoji made of several code points.
+func graphemeBoundaries(s string) []int {
+	var offsets []int // The byte offset of each rune, as invalid bytes are converted to a single rune of a different length.
+	var runes []rune
+	for i, r := range s {
+		offsets = append(offsets, i)
+		runes = append(runes, r)
+	}
+	offsets = append(offsets, len(s))
+
+	var seg segmenter.Segmenter
+	seg.Init(runes)
+	boundaries := []int{0}
+	for iter := seg.GraphemeIterator(); iter.Next(); {
+		g := iter.Grapheme()
+		boundaries = append(boundaries, offsets[g.Offset+len(g.Text)])
+	}
+	return boundaries
+}
+
+// Word classes. Adjacent runes of the same class other than wordNone form a word, so that Japanese text is split where it changes between kanji, hiragana, and katakana.
+const (
+	wordNone = iota
+	wordHan
+	wordHiragana
+	wordKatakana
+	wordOther
+)
+
+// wordClass returns the word class of r.
+func wordClass(r rune) int {
+	switch {
+	case unicode.Is(unicode.Han, r):
+		return wordHan
+	case unicode.Is(unicode.Hiragana, r):
+		return wordHiragana
+	case unicode.Is(unicode.Katakana, r), r == 'ー':
+		return wordKatakana
+	case unicode.IsLetter(r), unicode.IsNumber(r), r == '_':
+		return wordOther
+	}
+	return wordNone
+}
+
+// wordSpans returns the start and end byte offsets of each word in s. Words are runs of letters and numbers, along with any marks on them and apostrophes within them such as in "don't", so spaces and other punctuation are not part of any word.
+func wordSpans(s string) (spans [][2]int) {
+	type classedRune struct {
+		offset int
+		class  int
+	}
+	var runes []classedRune
+	for i, r := range s {
+		c := wordClass(r)
+		if unicode.Is(unicode.Mn, r) && len(runes) > 0 {
+			c = runes[len(runes)-1].class
+		}
+		runes = append(runes, classedRune{i, c})
+	}
+	// Apostrophes within words are part of them.
+	for j := 1; j < len(runes)-1; j++ {
+		if r, _ := utf8.DecodeRuneInString(s[runes[j].offset:]); (r == '\'' || r == '’') && runes[j-1].class == wordOther && runes[j+1].class == wordOther {
+			runes[j].class = wordOther
+		}
+	}
+	for j := 0; j < len(runes); j++ {
+		c := runes[j].class
+		if c == wordNone {
+			continue
+		}
+		start := runes[j].offset
+		for j+1 < len(runes) && runes[j+1].class == c {
+			j++
+		}
+		end := len(s)
+		if j+1 < len(runes) {
+			end = runes[j+1].offset
+		}
+		spans = append(spans, [2]int{start, end})
+	}
+	return spans
+}
+
+// prevWordStart returns the start of the last word that starts before i, or 0 if there is none.
+func prevWordStart(spans [][2]int, i int) int {
+	for j := len(spans) - 1; j >= 0; j-- {
+		if spans[j][0] < i {
+			return spans[j][0]
+		}
+	}
+	return 0
+}
+
+// nextWordEnd returns the end of the first word that ends after i, or end if there is none.
+func nextWordEnd(spans [][2]int, i, end int) int {
+	for _, span := range spans {
+		if span[1] > i {
+			return span[1]
+		}
+	}
+	return end
+}
+
+// wordAt returns the span of the word at i. If there is no word at i, the span between the words around it is returned instead, so that the spaces between words can be selected as with a word.
+func wordAt(spans [][2]int, i, end int) (start, stop int) {
+	stop = end
+	for _, span := range spans {
+		if span[0] <= i && i < span[1] {
+			return span[0], span[1]
+		}
+		if span[1] <= i {
+			start = span[1]
+		} else {
+			stop = span[0]
+			break
+		}
+	}
+	// Prefer the word just before i, as when clicking at the end of a word.
+	for _, span := range spans {
+		if span[1] == i && span[1] > span[0] && start == i {
+			return span[0], span[1]
+		}
+	}
+	return start, stop
+}
+
+// prevBoundary returns the last boundary before i, or 0 if there is none.
+func prevBoundary(boundaries []int, i int) int {
+	for j := len(boundaries) - 1; j >= 0; j-- {
+		if boundaries[j] < i {
+			return boundaries[j]
+		}
+	}
+	return 0
+}
+
+// nextBoundary returns the first boundary after i, or the last boundary if there is none.
+func nextBoundary(boundaries []int, i int) int {
+	for _, b := range boundaries {
+		if b > i {
+			return b
+		}
+	}
+	return boundaries[len(boundaries)-1]
+}
+
+// snapBoundary returns i if it is a boundary, or otherwise the first boundary after it.
+func snapBoundary(boundaries []int, i int) int {
+	return nextBoundary(boundaries, i-1)
+}
+
+// boundaryIndex returns how many grapheme clusters come before the byte offset i.
+func boundaryIndex(boundaries []int, i int) int {
+	for j, b := range boundaries {
+		if b >= i {
+			return j
+		}
+	}
+	return len(boundaries) - 1
+}
@@ -0,0 +1,108 @@
+package widgets
+
+import (
+	"slices"
+	"testing"
+	"unicode/utf8"
+)
+
+// graphemeTests are strings along with the grapheme cluster boundaries expected in them.
+var graphemeTests = []struct {
+	name       string
+	text       string
+	boundaries []int
+}{
+	{"empty", "", []int{0}},
+	{"ascii", "abc", []int{0, 1, 2, 3}},
+	{"precomposed", "caf\u00e9", []int{0, 1, 2, 3, 5}},
+	{"combining mark", "cafe\u0301", []int{0, 1, 2, 3, 6}},
+	{"combining marks", "a\u0323\u0301b", []int{0, 5, 6}},
+	{"japanese", "日本語です", []int{0, 3, 6, 9, 12, 15}},
+	{"combining dakuten", "\u304b\u3099き", []int{0, 6, 9}},
+	{"hangul jamo", "\u1100\u1161\u11a8!", []int{0, 9, 10}},
+	{"zwj emoji", "a\U0001F468\u200d\U0001F469\u200d\U0001F467b", []int{0, 1, 19, 20}},
+	{"skin tone", "\U0001F44D\U0001F3FD\U0001F44D", []int{0, 8, 12}},
+	{"flags", "\U0001F1EF\U0001F1F5\U0001F1FA\U0001F1F8", []int{0, 8, 16}},
+	{"crlf", "a\r\nb", []int{0, 1, 3, 4}},
+	{"invalid", "a\xffb", []int{0, 1, 2, 3}},
+}
+
+func TestGraphemeBoundaries(t *testing.T) {
+	for _, tt := range graphemeTests {
+		t.Run(tt.name, func(t *testing.T) {
+			if b := graphemeBoundaries(tt.text); !slices.Equal(b, tt.boundaries) {
+				t.Errorf("expected boundaries %v, got %v", tt.boundaries, b)
+			}
+		})
+	}
+}
+
+func TestGraphemeBoundariesOnRunes(t *testing.T) {
+	for _, tt := range graphemeTests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := graphemeBoundaries(tt.text)
+			if b[0] != 0 || b[len(b)-1] != len(tt.text) {
+				t.Fatalf("expected boundaries from 0 through %d, got %v", len(tt.text), b)
+			}
+			for j, i := range b {
+				if j > 0 && i <= b[j-1] {
+					t.Errorf("expected increasing boundaries, got %v", b)
+				}
+				if i < len(tt.text) && !utf8.RuneStart(tt.text[i]) {
+					t.Errorf("boundary %d is within a rune", i)
+				}
+			}
+		})
+	}
+}
+
+func TestPrevNextBoundary(t *testing.T) {
+	for _, tt := range graphemeTests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := tt.boundaries
+			for i := 0; i <= len(tt.text); i++ {
+				prev := prevBoundary(b, i)
+				if !slices.Contains(b, prev) {
+					t.Errorf("prevBoundary(%d) = %d, which is within a cluster", i, prev)
+				}
+				if i > 0 && prev >= i {
+					t.Errorf("prevBoundary(%d) = %d, which is not before it", i, prev)
+				}
+
+				next := nextBoundary(b, i)
+				if !slices.Contains(b, next) {
+					t.Errorf("nextBoundary(%d) = %d, which is within a cluster", i, next)
+				}
+				if i < len(tt.text) && next <= i {
+					t.Errorf("nextBoundary(%d) = %d, which is not after it", i, next)
+				}
+
+				snap := snapBoundary(b, i)
+				if !slices.Contains(b, snap) || snap < i {
+					t.Errorf("snapBoundary(%d) = %d, which is not the next boundary", i, snap)
+				}
+				if slices.Contains(b, i) && snap != i {
+					t.Errorf("snapBoundary(%d) = %d, expected the boundary itself", i, snap)
+				}
+			}
+			// Stepping through boundaries visits each in turn.
+			for j := 0; j+1 < len(b); j++ {
+				if next := nextBoundary(b, b[j]); next != b[j+1] {
+					t.Errorf("nextBoundary(%d) = %d, expected %d", b[j], next, b[j+1])
+				}
+				if prev := prevBoundary(b, b[j+1]); prev != b[j] {
+					t.Errorf("prevBoundary(%d) = %d, expected %d", b[j+1], prev, b[j])
+				}
+			}
+		})
+	}
+}
+
+func TestBoundaryIndex(t *testing.T) {
+	b := graphemeBoundaries("é日👨‍👩‍👧")
+	for j, i := range b {
+		if n := boundaryIndex(b, i); n != j {
+			t.Errorf("boundaryIndex(%d) = %d, expected %d", i, n, j)
+		}
+	}
+}
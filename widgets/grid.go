@@ -0,0 +1,214 @@
+package widgets
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/kettek/rebui"
+)
+
+// Grid arranges its children into cells defined by its Columns and Rows tracks. Children are placed by their Column and Row, spanning ColumnSpan and RowSpan cells, or are otherwise placed in the next free cell, left to right and top to bottom. Rows beyond those defined repeat the last row track, or share the remaining space equally if there are no row tracks.
+//
+// A child without a Width or Height fills its cell along that axis. Otherwise it is positioned within its cell by the grid's HorizontalAlign and VerticalAlign, where AlignStretch fills the cell regardless.
+type Grid struct {
+	Basic
+	gapX, gapY    float64
+	columns, rows []rebui.Track
+	valign        rebui.Alignment
+	halign        rebui.Alignment
+}
+
+// AssignGap sets the spacing between cells.
+func (g *Grid) AssignGap(x, y float64) {
+	g.gapX = x
+	g.gapY = y
+}
+
+// AssignTracks sets the column and row tracks.
+func (g *Grid) AssignTracks(columns, rows []rebui.Track) {
+	g.columns = columns
+	g.rows = rows
+}
+
+// AssignVerticalAlignment sets the vertical alignment of children within their cells.
+func (g *Grid) AssignVerticalAlignment(align rebui.Alignment) {
+	g.valign = align
+}
+
+// AssignHorizontalAlignment sets the horizontal alignment of children within their cells.
+func (g *Grid) AssignHorizontalAlignment(align rebui.Alignment) {
+	g.halign = align
+}
+
+func (g *Grid) Draw(screen *ebiten.Image, sop *ebiten.DrawImageOptions) {
+	// NOP, same as Area.
+}
+
+// gridPlacement is the cell range occupied by a child, in 0-based track indices.
+type gridPlacement struct {
+	column, row         int
+	columnSpan, rowSpan int
+}
+
+// place assigns each child to a range of cells, returning the placements along with the number of columns and rows used.
+func (g *Grid) place(children []*rebui.Node) (placements []gridPlacement, columns, rows int) {
+	columns = max(1, len(g.columns))
+	for _, c := range children {
+		columns = max(columns, c.Column+max(1, c.ColumnSpan)-1)
+	}
+
+	occupied := make(map[[2]int]bool)
+	fits := func(p gridPlacement) bool {
+		if p.column+p.columnSpan > columns {
+			return false
+		}
+		for x := p.column; x < p.column+p.columnSpan; x++ {
+			for y := p.row; y < p.row+p.rowSpan; y++ {
+				if occupied[[2]int{x, y}] {
+					return false
+				}
+			}
+		}
+		return true
+	}
+
+	placements = make([]gridPlacement, len(children))
+	// Explicitly placed children claim their cells first, so that automatically placed children flow around them.
+	for i, c := range children {
+		placements[i] = gridPlacement{
+			column:     c.Column - 1,
+			row:        c.Row - 1,
+			columnSpan: min(max(1, c.ColumnSpan), columns),
+			rowSpan:    max(1, c.RowSpan),
+		}
+		if c.Column > 0 && c.Row > 0 {
+			p := placements[i]
+			for x := p.column; x < p.column+p.columnSpan; x++ {
+				for y := p.row; y < p.row+p.rowSpan; y++ {
+					occupied[[2]int{x, y}] = true
+				}
+			}
+		}
+	}
+
+	var cursorColumn, cursorRow int
+	for i, c := range children {
+		p := &placements[i]
+		if c.Column <= 0 || c.Row <= 0 {
+			switch {
+			case c.Column > 0:
+				// Fixed column, so find the first row with space.
+				for p.row = 0; !fits(*p); p.row++ {
+				}
+			case c.Row > 0:
+				// Fixed row, so find the first column with space. If there is none, the child overlaps the end of the row.
+				for p.column = 0; p.column+p.columnSpan < columns && !fits(*p); p.column++ {
+				}
+			default:
+				p.column, p.row = cursorColumn, cursorRow
+				for !fits(*p) {
+					p.column++
+					if p.column+p.columnSpan > columns {
+						p.column = 0
+						p.row++
+					}
+				}
+				cursorColumn, cursorRow = p.column+p.columnSpan, p.row
+			}
+			for x := p.column; x < p.column+p.columnSpan; x++ {
+				for y := p.row; y < p.row+p.rowSpan; y++ {
+					occupied[[2]int{x, y}] = true
+				}
+			}
+		}
+		rows = max(rows, p.row+p.rowSpan)
+	}
+	rows = max(rows, len(g.rows))
+
+	return placements, columns, rows
+}
+
+// resolveTracks returns the size of count tracks within the given space. Tracks beyond those defined repeat the last defined track, or are 1fr if none are defined.
+func resolveTracks(defined []rebui.Track, count int, space, gap float64) []float64 {
+	tracks := make([]rebui.Track, count)
+	for i := range tracks {
+		switch {
+		case i < len(defined):
+			tracks[i] = defined[i]
+		case len(defined) > 0:
+			tracks[i] = defined[len(defined)-1]
+		default:
+			tracks[i] = rebui.Track{Fraction: 1}
+		}
+	}
+
+	free := space - gap*float64(max(0, count-1))
+	var fractions float64
+	for _, t := range tracks {
+		if t.Fraction > 0 {
+			fractions += t.Fraction
+		} else {
+			free -= t.Size
+		}
+	}
+	free = max(0, free)
+
+	sizes := make([]float64, count)
+	for i, t := range tracks {
+		if t.Fraction > 0 {
+			sizes[i] = free * t.Fraction / fractions
+		} else {
+			sizes[i] = t.Size
+		}
+	}
+	return sizes
+}
+
+// trackSpan returns the start and size of a span of tracks.
+func trackSpan(sizes []float64, start float64, gap float64, index, span int) (float64, float64) {
+	for _, s := range sizes[:index] {
+		start += s + gap
+	}
+	var size float64
+	for _, s := range sizes[index : index+span] {
+		size += s
+	}
+	return start, size + gap*float64(span-1)
+}
+
+// Cells returns the cell of each child.
+func (g *Grid) Cells(ctx rebui.LayoutContext, children []*rebui.Node) []rebui.LayoutContext {
+	placements, columns, rows := g.place(children)
+	columnSizes := resolveTracks(g.columns, columns, ctx.OuterWidth, g.gapX)
+	rowSizes := resolveTracks(g.rows, rows, ctx.OuterHeight, g.gapY)
+
+	cells := make([]rebui.LayoutContext, len(placements))
+	for i, p := range placements {
+		cells[i].OuterX, cells[i].OuterWidth = trackSpan(columnSizes, ctx.OuterX, g.gapX, p.column, p.columnSpan)
+		cells[i].OuterY, cells[i].OuterHeight = trackSpan(rowSizes, ctx.OuterY, g.gapY, p.row, p.rowSpan)
+	}
+	return cells
+}
+
+// Arrange positions the children within their cells.
+func (g *Grid) Arrange(ctx rebui.LayoutContext, children []*rebui.Arrangement) {
+	nodes := make([]*rebui.Node, len(children))
+	for i, c := range children {
+		nodes[i] = c.Node
+	}
+	cells := g.Cells(ctx, nodes)
+
+	for i, c := range children {
+		cell := cells[i]
+		if c.Node.Width == "" || g.halign == rebui.AlignStretch {
+			c.Width = cell.OuterWidth
+		}
+		if c.Node.Height == "" || g.valign == rebui.AlignStretch {
+			c.Height = cell.OuterHeight
+		}
+		c.X = cell.OuterX + (cell.OuterWidth-c.Width)*alignmentFactor(g.halign)
+		c.Y = cell.OuterY + (cell.OuterHeight-c.Height)*alignmentFactor(g.valign)
+	}
+}
+
+func init() {
+	rebui.RegisterWidget("Grid", &Grid{})
+}
@@ -0,0 +1,84 @@
+package widgets
+
+// defaultHistoryDepth is the most edits that can be undone when a widget's HistoryDepth is 0.
+const defaultHistoryDepth = 100
+
+// textState is the text of a text editing widget along with its cursor and selection.
+type textState struct {
+	text        string
+	cursor      int
+	selectStart int
+	selectEnd   int
+}
+
+// editKind is the kind of an edit, used to merge consecutive edits of the same kind into one.
+type editKind int
+
+const (
+	editOther    editKind = iota // The edit is never merged, such as a paste.
+	editTyping                   // The edit is a typed character.
+	editDeleting                 // The edit is a Backspace or Delete without a selection.
+)
+
+// textHistory is the edit history of a text editing widget. It holds the states from before each edit, so that undoing restores the state before the last edit and redoing restores the state that was undone.
+type textHistory struct {
+	undos []textState
+	redos []textState
+	last  editKind  // The kind of the last edit.
+	after textState // The state after the last edit.
+}
+
+// record saves the state from before an edit of the given kind. If the edit continues an edit of the same kind from where it left off, such as typing the next character of a word, the two are merged by keeping the state from before the first. The oldest states are dropped beyond the given depth, or beyond defaultHistoryDepth if it is 0.
+func (h *textHistory) record(before textState, kind editKind, depth int) {
+	h.redos = h.redos[:0]
+	if kind != editOther && kind == h.last && before == h.after {
+		return
+	}
+	h.last = kind
+	h.undos = append(h.undos, before)
+	if depth <= 0 {
+		depth = defaultHistoryDepth
+	}
+	if len(h.undos) > depth {
+		h.undos = append(h.undos[:0], h.undos[len(h.undos)-depth:]...)
+	}
+}
+
+// edited notes the state after an edit, which the next edit must start from to be merged with it.
+func (h *textHistory) edited(after textState) {
+	h.after = after
+}
+
+// split keeps the next edit from being merged with the last.
+func (h *textHistory) split() {
+	h.last = editOther
+}
+
+// undo returns the state from before the last edit, saving the current state to be redone.
+func (h *textHistory) undo(current textState) (textState, bool) {
+	if len(h.undos) == 0 {
+		return current, false
+	}
+	s := h.undos[len(h.undos)-1]
+	h.undos = h.undos[:len(h.undos)-1]
+	h.redos = append(h.redos, current)
+	h.split()
+	return s, true
+}
+
+// redo returns the state that was last undone, saving the current state to be undone again.
+func (h *textHistory) redo(current textState) (textState, bool) {
+	if len(h.redos) == 0 {
+		return current, false
+	}
+	s := h.redos[len(h.redos)-1]
+	h.redos = h.redos[:len(h.redos)-1]
+	h.undos = append(h.undos, current)
+	h.split()
+	return s, true
+}
+
+// clear removes all history.
+func (h *textHistory) clear() {
+	*h = textHistory{}
+}
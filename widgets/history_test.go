@@ -0,0 +1,176 @@
+package widgets
+
+import "testing"
+
+// historyEdit is an edit applied to a textHistory in tests, changing the text from before to after.
+type historyEdit struct {
+	before, after string
+	kind          editKind
+}
+
+func applyEdits(h *textHistory, depth int, edits []historyEdit) {
+	for _, e := range edits {
+		h.record(textState{text: e.before, cursor: len(e.before)}, e.kind, depth)
+		h.edited(textState{text: e.after, cursor: len(e.after)})
+	}
+}
+
+// undoAll undoes every edit from the current text, returning the text restored by each undo.
+func undoAll(h *textHistory, current string) (texts []string) {
+	s := textState{text: current, cursor: len(current)}
+	for {
+		var ok bool
+		if s, ok = h.undo(s); !ok {
+			return
+		}
+		texts = append(texts, s.text)
+	}
+}
+
+func TestHistoryCoalescing(t *testing.T) {
+	tests := []struct {
+		name     string
+		edits    []historyEdit
+		expected []string // The texts restored by undoing each edit in turn.
+	}{
+		{
+			"typing merges",
+			[]historyEdit{{"", "a", editTyping}, {"a", "ab", editTyping}, {"ab", "abc", editTyping}},
+			[]string{""},
+		},
+		{
+			"deleting merges",
+			[]historyEdit{{"abc", "ab", editDeleting}, {"ab", "a", editDeleting}},
+			[]string{"abc"},
+		},
+		{
+			"other edits never merge",
+			[]historyEdit{{"", "a", editOther}, {"a", "ab", editOther}},
+			[]string{"a", ""},
+		},
+		{
+			"a change of kind does not merge",
+			[]historyEdit{{"", "a", editTyping}, {"a", "ab", editTyping}, {"ab", "a", editDeleting}, {"a", "ac", editTyping}},
+			[]string{"a", "ab", ""},
+		},
+		{
+			"edits that do not continue from the last do not merge",
+			[]historyEdit{{"", "a", editTyping}, {"b", "bc", editTyping}},
+			[]string{"b", ""},
+		},
+	}
+	for _, tt := range tests {
+		var h textHistory
+		applyEdits(&h, 0, tt.edits)
+		got := undoAll(&h, tt.edits[len(tt.edits)-1].after)
+		if len(got) != len(tt.expected) {
+			t.Errorf("%s: expected undos to restore %q, got %q", tt.name, tt.expected, got)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.expected[i] {
+				t.Errorf("%s: expected undos to restore %q, got %q", tt.name, tt.expected, got)
+				break
+			}
+		}
+	}
+}
+
+func TestHistorySplit(t *testing.T) {
+	var h textHistory
+	applyEdits(&h, 0, []historyEdit{{"", "a", editTyping}, {"a", "a ", editTyping}})
+	h.split()
+	applyEdits(&h, 0, []historyEdit{{"a ", "a b", editTyping}, {"a b", "a bc", editTyping}})
+	got := undoAll(&h, "a bc")
+	if len(got) != 2 || got[0] != "a " || got[1] != "" {
+		t.Errorf("expected a split to undo each word separately, got %q", got)
+	}
+}
+
+func TestHistoryDepth(t *testing.T) {
+	tests := []struct {
+		depth, edits, undos int
+	}{
+		{0, 5, 5},
+		{0, defaultHistoryDepth + 10, defaultHistoryDepth},
+		{3, 2, 2},
+		{3, 3, 3},
+		{3, 5, 3},
+		{1, 5, 1},
+	}
+	for _, tt := range tests {
+		var h textHistory
+		text := ""
+		for i := 0; i < tt.edits; i++ {
+			applyEdits(&h, tt.depth, []historyEdit{{text, text + "a", editOther}})
+			text += "a"
+		}
+		got := undoAll(&h, text)
+		if len(got) != tt.undos {
+			t.Errorf("depth %d: expected %d edits to leave %d undos, got %d", tt.depth, tt.edits, tt.undos, len(got))
+			continue
+		}
+		// The oldest edits are the ones dropped.
+		if oldest := got[len(got)-1]; len(oldest) != tt.edits-tt.undos {
+			t.Errorf("depth %d: expected the oldest undo to restore %d characters, got %q", tt.depth, tt.edits-tt.undos, oldest)
+		}
+	}
+}
+
+func TestHistoryRedo(t *testing.T) {
+	tests := []struct {
+		name    string
+		undos   int
+		edit    bool // If an edit is made after undoing.
+		redos   []string
+		current string // The text once everything is redone.
+	}{
+		{"redo all", 2, false, []string{"ab", "abc"}, "abc"},
+		{"redo some", 1, false, []string{"abc"}, "abc"},
+		{"an edit truncates", 1, true, nil, "abx"},
+		{"nothing undone", 0, false, nil, "abc"},
+	}
+	for _, tt := range tests {
+		var h textHistory
+		applyEdits(&h, 0, []historyEdit{{"a", "ab", editOther}, {"ab", "abc", editOther}})
+		s := textState{text: "abc", cursor: 3}
+		for i := 0; i < tt.undos; i++ {
+			s, _ = h.undo(s)
+		}
+		if tt.edit {
+			applyEdits(&h, 0, []historyEdit{{s.text, s.text + "x", editOther}})
+			s = textState{text: s.text + "x", cursor: len(s.text) + 1}
+		}
+		var redos []string
+		for {
+			var ok bool
+			if s, ok = h.redo(s); !ok {
+				break
+			}
+			redos = append(redos, s.text)
+		}
+		if len(redos) != len(tt.redos) || s.text != tt.current {
+			t.Errorf("%s: expected redos of %q ending with %q, got %q ending with %q", tt.name, tt.redos, tt.current, redos, s.text)
+			continue
+		}
+		for i := range redos {
+			if redos[i] != tt.redos[i] {
+				t.Errorf("%s: expected redos of %q, got %q", tt.name, tt.redos, redos)
+				break
+			}
+		}
+	}
+}
+
+func TestHistoryClear(t *testing.T) {
+	var h textHistory
+	applyEdits(&h, 0, []historyEdit{{"", "a", editOther}, {"a", "ab", editOther}})
+	s, _ := h.undo(textState{text: "ab", cursor: 2})
+	h.clear()
+	if _, ok := h.undo(s); ok {
+		t.Error("expected nothing to undo after clearing")
+	}
+	if _, ok := h.redo(s); ok {
+		t.Error("expected nothing to redo after clearing")
+	}
+}
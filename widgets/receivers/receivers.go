@@ -12,6 +12,81 @@ type PointerGlobalMove interface {
 	HandlePointerGlobalMove(*events.PointerMove)
 }
 
+// PointerWheel is used to receive pointer wheel events. The event bubbles up to the element's parents unless it is canceled.
+type PointerWheel interface {
+	HandlePointerWheel(*events.PointerWheel)
+}
+
+// PointerTap is used to receive pointer tap events. This occurs when the element is pressed and released without the pointer moving far, with a count of successive taps.
+type PointerTap interface {
+	HandlePointerTap(*events.PointerTap)
+}
+
+// PointerDoubleClick is used to receive pointer double click events. This occurs along with the second of two taps in quick succession.
+type PointerDoubleClick interface {
+	HandlePointerDoubleClick(*events.PointerDoubleClick)
+}
+
+// PointerLongPress is used to receive pointer long press events. This occurs when the element is pressed and held without the pointer moving far.
+type PointerLongPress interface {
+	HandlePointerLongPress(*events.PointerLongPress)
+}
+
+// Pinch is used to receive pinch events. This occurs when two touches that began over the element move closer together or further apart, such as to zoom.
+type Pinch interface {
+	HandlePinch(*events.Pinch)
+}
+
+// Rotate is used to receive rotate events. This occurs when two touches that began over the element turn around each other.
+type Rotate interface {
+	HandleRotate(*events.Rotate)
+}
+
+// Pan is used to receive pan events. This occurs when two touches that began over the element move together.
+type Pan interface {
+	HandlePan(*events.Pan)
+}
+
+// DragStart is used to receive drag start events. This occurs on a draggable element when a pointer pressed on it moves far enough to begin a drag.
+type DragStart interface {
+	HandleDragStart(*events.DragStart)
+}
+
+// DragEnter is used to receive drag enter events. This occurs when a drag moves over the element, which must accept it to allow a drop.
+type DragEnter interface {
+	HandleDragEnter(*events.DragEnter)
+}
+
+// DragOver is used to receive drag over events. This occurs when a drag moves within the element, which must accept it to allow a drop.
+type DragOver interface {
+	HandleDragOver(*events.DragOver)
+}
+
+// DragLeave is used to receive drag leave events. This occurs when a drag leaves the element.
+type DragLeave interface {
+	HandleDragLeave(*events.DragLeave)
+}
+
+// Drop is used to receive drop events. This occurs when a drag is released over the element after it accepted it.
+type Drop interface {
+	HandleDrop(*events.Drop)
+}
+
+// DragEnd is used to receive drag end events. This occurs on the dragged element when its drag ends, whether or not it was dropped.
+type DragEnd interface {
+	HandleDragEnd(*events.DragEnd)
+}
+
+// GotCapture is used to receive got capture events. This occurs when the element captures a pointer.
+type GotCapture interface {
+	HandleGotCapture(*events.GotCapture)
+}
+
+// LostCapture is used to receive lost capture events. This occurs when the element loses its capture of a pointer, such as when the pointer is released.
+type LostCapture interface {
+	HandleLostCapture(*events.LostCapture)
+}
+
 // PointerIn is used to receive pointer in events.
 type PointerIn interface {
 	HandlePointerIn(*events.PointerIn)
@@ -67,7 +142,42 @@ type KeyInput interface {
 	HandleKeyInput(*events.KeyInput)
 }
 
+// GamepadButtonPress is used to receive gamepad button press events. Only the focused element will receive this event.
+type GamepadButtonPress interface {
+	HandleGamepadButtonPress(*events.GamepadButtonPress)
+}
+
+// GamepadButtonRelease is used to receive gamepad button release events. Only the focused element will receive this event.
+type GamepadButtonRelease interface {
+	HandleGamepadButtonRelease(*events.GamepadButtonRelease)
+}
+
+// GamepadAxis is used to receive gamepad axis events. Only the focused element will receive this event.
+type GamepadAxis interface {
+	HandleGamepadAxis(*events.GamepadAxis)
+}
+
+// Activate is used to receive activate events. Only the focused element will receive this event.
+type Activate interface {
+	HandleActivate(*events.Activate)
+}
+
+// Cancel is used to receive cancel events, which are sent to the focused element and then its parents.
+type Cancel interface {
+	HandleCancel(*events.Cancel)
+}
+
+// Update is used to receive a call on every Layout update, such as for animations.
+type Update interface {
+	HandleUpdate()
+}
+
 // Generate is used when an element is generated. This only happens once.
 type Generate interface {
 	HandleGenerate()
 }
+
+// Capture is used to receive events of any type during their capture phase, before they reach the descendant they are dispatched to. Stopping an event's propagation here keeps it from reaching the descendant.
+type Capture interface {
+	HandleCapture(any)
+}
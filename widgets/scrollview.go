@@ -0,0 +1,226 @@
+package widgets
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/kettek/rebui"
+)
+
+// Scrollbar and inertia settings for ScrollView.
+const (
+	scrollbarSize      = 6
+	scrollbarMinLength = 16
+	scrollFriction     = 0.92 // How much of the scroll velocity remains each update once a touch drag is released.
+	scrollMinVelocity  = 0.1
+	scrollWheelStep    = 24 // How far a single step of the mouse wheel scrolls.
+)
+
+// scrollDrag is what a ScrollView is currently being dragged by.
+type scrollDrag int
+
+const (
+	scrollDragNone scrollDrag = iota
+	scrollDragContent
+	scrollDragVertical
+	scrollDragHorizontal
+)
+
+// ScrollView clips its children to its bounds and scrolls them with the mouse wheel, touch drags with inertia, or by dragging its scrollbars. Children are positioned as usual, so the area they cover determines how far the view can scroll.
+type ScrollView struct {
+	Basic
+	backgroundColor          color.Color
+	foregroundColor          color.Color
+	scrollX, scrollY         float64
+	contentWidth             float64
+	contentHeight            float64
+	velocityX, velocityY     float64
+	drag                     scrollDrag
+	dragPointer              rebui.PointerID
+	dragOffsetX, dragOffsetY float64 // Where a scrollbar thumb was grabbed, relative to the thumb's start.
+}
+
+// AssignBackgroundColor sets the background color.
+func (s *ScrollView) AssignBackgroundColor(clr color.Color) {
+	s.backgroundColor = clr
+}
+
+// AssignForegroundColor sets the color of the scrollbars.
+func (s *ScrollView) AssignForegroundColor(clr color.Color) {
+	s.foregroundColor = clr
+}
+
+// AssignContentSize sets the size of the area covered by the children.
+func (s *ScrollView) AssignContentSize(width, height float64) {
+	s.contentWidth = width
+	s.contentHeight = height
+	s.ScrollTo(s.scrollX, s.scrollY)
+}
+
+// ScrollOffset returns how far the children are scrolled.
+func (s *ScrollView) ScrollOffset() (x, y float64) {
+	return s.scrollX, s.scrollY
+}
+
+// ScrollBy scrolls by the given distance, returning if the offset changed.
+func (s *ScrollView) ScrollBy(dx, dy float64) bool {
+	return s.ScrollTo(s.scrollX+dx, s.scrollY+dy)
+}
+
+// ScrollTo scrolls to the given offset, clamped to the content, returning if the offset changed.
+func (s *ScrollView) ScrollTo(x, y float64) bool {
+	x = max(0, min(x, s.contentWidth-s.Width))
+	y = max(0, min(y, s.contentHeight-s.Height))
+	if x == s.scrollX && y == s.scrollY {
+		return false
+	}
+	s.scrollX, s.scrollY = x, y
+	return true
+}
+
+// verticalThumb returns the start and length of the vertical scrollbar's thumb, relative to the view. If ok is false, there is nothing to scroll vertically.
+func (s *ScrollView) verticalThumb() (start, length float64, ok bool) {
+	return scrollThumb(s.scrollY, s.Height, s.contentHeight)
+}
+
+// horizontalThumb returns the start and length of the horizontal scrollbar's thumb, relative to the view. If ok is false, there is nothing to scroll horizontally.
+func (s *ScrollView) horizontalThumb() (start, length float64, ok bool) {
+	return scrollThumb(s.scrollX, s.Width, s.contentWidth)
+}
+
+func scrollThumb(offset, viewport, content float64) (start, length float64, ok bool) {
+	if content <= viewport || viewport <= 0 {
+		return 0, 0, false
+	}
+	length = max(scrollbarMinLength, viewport*viewport/content)
+	start = offset / (content - viewport) * (viewport - length)
+	return start, length, true
+}
+
+// HandlePointerPress starts dragging a scrollbar if one was pressed, or the content if the press was a touch. The pointer is captured for the drag.
+func (s *ScrollView) HandlePointerPress(evt rebui.EventPointerPress) {
+	s.velocityX, s.velocityY = 0, 0
+	s.drag = scrollDragNone
+	s.dragPointer = evt.ID()
+
+	if start, length, ok := s.verticalThumb(); ok && evt.RelativeX >= s.Width-scrollbarSize {
+		s.drag = scrollDragVertical
+		s.dragOffsetY = evt.RelativeY - start
+		if s.dragOffsetY < 0 || s.dragOffsetY > length {
+			// Pressing the track outside of the thumb centers the thumb there.
+			s.dragOffsetY = length / 2
+			s.dragScrollbar(evt.RelativeX, evt.RelativeY)
+		}
+	} else if start, length, ok := s.horizontalThumb(); ok && evt.RelativeY >= s.Height-scrollbarSize {
+		s.drag = scrollDragHorizontal
+		s.dragOffsetX = evt.RelativeX - start
+		if s.dragOffsetX < 0 || s.dragOffsetX > length {
+			s.dragOffsetX = length / 2
+			s.dragScrollbar(evt.RelativeX, evt.RelativeY)
+		}
+	} else if evt.TouchID > 0 {
+		s.drag = scrollDragContent
+	}
+	if s.drag != scrollDragNone {
+		evt.CapturePointer()
+	}
+}
+
+// dragScrollbar scrolls so that the grabbed point of the dragged thumb is at the given position.
+func (s *ScrollView) dragScrollbar(x, y float64) {
+	switch s.drag {
+	case scrollDragVertical:
+		if _, length, ok := s.verticalThumb(); ok && s.Height > length {
+			s.ScrollTo(s.scrollX, (y-s.dragOffsetY)/(s.Height-length)*(s.contentHeight-s.Height))
+		}
+	case scrollDragHorizontal:
+		if _, length, ok := s.horizontalThumb(); ok && s.Width > length {
+			s.ScrollTo((x-s.dragOffsetX)/(s.Width-length)*(s.contentWidth-s.Width), s.scrollY)
+		}
+	}
+}
+
+// HandlePointerMove continues any drag.
+func (s *ScrollView) HandlePointerMove(evt rebui.EventPointerMove) {
+	if s.drag == scrollDragNone || evt.ID() != s.dragPointer {
+		return
+	}
+	if s.drag == scrollDragContent {
+		s.ScrollBy(-evt.DX, -evt.DY)
+		s.velocityX, s.velocityY = -evt.DX, -evt.DY
+		return
+	}
+	s.dragScrollbar(evt.RelativeX, evt.RelativeY)
+}
+
+// HandlePointerRelease ends any drag. Releasing a content drag leaves the content moving with inertia.
+func (s *ScrollView) HandlePointerRelease(evt rebui.EventPointerRelease) {
+	if evt.ID() != s.dragPointer {
+		return
+	}
+	if s.drag != scrollDragContent {
+		s.velocityX, s.velocityY = 0, 0
+	}
+	s.drag = scrollDragNone
+}
+
+// HandleLostCapture ends any drag if its pointer capture is taken away.
+func (s *ScrollView) HandleLostCapture(evt rebui.EventLostCapture) {
+	if evt.PointerID == s.dragPointer && s.drag != scrollDragNone {
+		s.drag = scrollDragNone
+		s.velocityX, s.velocityY = 0, 0
+	}
+}
+
+// HandlePointerWheel scrolls by the wheel's movement. The event is canceled if the view scrolled, so that containing views only scroll once this one can scroll no further.
+func (s *ScrollView) HandlePointerWheel(evt rebui.EventPointerWheel) {
+	if s.ScrollBy(-evt.DX*scrollWheelStep, -evt.DY*scrollWheelStep) {
+		evt.Cancel()
+	}
+}
+
+// HandleUpdate applies any inertia.
+func (s *ScrollView) HandleUpdate() {
+	if s.drag != scrollDragNone || (s.velocityX == 0 && s.velocityY == 0) {
+		return
+	}
+	if !s.ScrollBy(s.velocityX, s.velocityY) {
+		s.velocityX, s.velocityY = 0, 0
+		return
+	}
+	s.velocityX *= scrollFriction
+	s.velocityY *= scrollFriction
+	if math.Abs(s.velocityX) < scrollMinVelocity && math.Abs(s.velocityY) < scrollMinVelocity {
+		s.velocityX, s.velocityY = 0, 0
+	}
+}
+
+func (s *ScrollView) Draw(screen *ebiten.Image, sop *ebiten.DrawImageOptions) {
+	if s.backgroundColor == nil {
+		return
+	}
+	x := sop.GeoM.Element(0, 2)
+	y := sop.GeoM.Element(1, 2)
+	vector.DrawFilledRect(screen, float32(x), float32(y), float32(s.Width), float32(s.Height), s.backgroundColor, true)
+}
+
+// DrawOverlay draws the scrollbars over the children.
+func (s *ScrollView) DrawOverlay(screen *ebiten.Image, sop *ebiten.DrawImageOptions) {
+	if s.foregroundColor == nil {
+		return
+	}
+	x := sop.GeoM.Element(0, 2)
+	y := sop.GeoM.Element(1, 2)
+	if start, length, ok := s.verticalThumb(); ok {
+		vector.DrawFilledRect(screen, float32(x+s.Width-scrollbarSize), float32(y+start), scrollbarSize, float32(length), s.foregroundColor, true)
+	}
+	if start, length, ok := s.horizontalThumb(); ok {
+		vector.DrawFilledRect(screen, float32(x+start), float32(y+s.Height-scrollbarSize), float32(length), scrollbarSize, s.foregroundColor, true)
+	}
+}
+
+func init() {
+	rebui.RegisterWidget("ScrollView", &ScrollView{})
+}
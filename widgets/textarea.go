@@ -0,0 +1,557 @@
+package widgets
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/kettek/rebui"
+	"github.com/kettek/rebui/blocks"
+	"github.com/kettek/rebui/clipboard"
+)
+
+// TextArea is a multi-line text input. Its text is wrapped as with the Text widget, by word unless another TextWrap is given, and it scrolls vertically to keep the cursor in view.
+type TextArea struct {
+	Basic
+	Border
+	text            string
+	boundaries      []int         // The byte offsets of the grapheme clusters in text, which the cursor and selection are kept on.
+	lines           []blocks.Text // The wrapped lines of text.
+	face            text.Face
+	wrap            rebui.Wrap
+	foregroundColor color.Color
+	backgroundColor color.Color
+	cursor          int
+	cursorX         float64 // The x position that moving the cursor up and down keeps nearest to.
+	showCursor      bool
+	cursorHidden    bool
+	lastTime        time.Time
+	selectInitial   int
+	selectStart     int
+	selectEnd       int
+	selecting       bool // If a pointer is dragging out a selection.
+	selectPointer   rebui.PointerID
+	ScrollY         float64
+	OnChange        func(string)
+	OnSubmit        func(string) // OnSubmit is called when Ctrl+Enter is pressed, as Enter starts a new line.
+	history         textHistory
+	HistoryDepth    int // HistoryDepth is the most edits that can be undone. If 0, 100 is used.
+}
+
+// normalizeText replaces Windows line endings and invalid UTF-8 so that the text can be wrapped and edited consistently.
+func normalizeText(s string) string {
+	return strings.ToValidUTF8(strings.ReplaceAll(s, "\r\n", "\n"), "\uFFFD")
+}
+
+func (w *TextArea) AssignWidth(width float64) {
+	w.Width = width
+	w.refreshLines()
+}
+
+func (w *TextArea) AssignHeight(height float64) {
+	w.Height = height
+	w.clampScroll()
+}
+
+// AssignText replaces the text, discarding the edit history.
+func (w *TextArea) AssignText(text string) {
+	w.setText(text)
+	w.history.clear()
+}
+
+// setText replaces the text, clearing the selection and keeping the cursor within it.
+func (w *TextArea) setText(text string) {
+	w.selectStart = 0
+	w.selectEnd = 0
+	w.text = normalizeText(text)
+	w.boundaries = graphemeBoundaries(w.text)
+	if w.cursor > len(w.text) {
+		w.cursor = len(w.text)
+	}
+	w.cursor = snapBoundary(w.boundaries, w.cursor)
+	w.refreshLines()
+	if w.OnChange != nil {
+		w.OnChange(w.text)
+	}
+}
+
+func (w *TextArea) AssignTextWrap(wrap rebui.Wrap) {
+	w.wrap = wrap
+	w.refreshLines()
+}
+
+func (w *TextArea) AssignForegroundColor(clr color.Color) {
+	w.foregroundColor = clr
+}
+
+func (w *TextArea) AssignBackgroundColor(clr color.Color) {
+	w.backgroundColor = clr
+}
+
+func (w *TextArea) AssignFontFace(face text.Face) {
+	w.face = face
+	w.refreshLines()
+}
+
+func (w *TextArea) AssignFontSize(size float64) {
+	// Re-use FontFace.
+	if textFace, ok := w.face.(*text.GoTextFace); ok {
+		txt := *textFace
+		txt.Size = size
+		w.face = &txt
+		w.refreshLines()
+	}
+}
+
+// GetCursor returns the text cursor, shown while the area is hovered.
+func (w *TextArea) GetCursor() rebui.Cursor {
+	return rebui.CursorText
+}
+
+// refreshLines wraps the text into lines.
+func (w *TextArea) refreshLines() {
+	if w.face == nil {
+		w.lines = []blocks.Text{{Text: w.text}}
+		return
+	}
+	wrap := w.wrap
+	if wrap == "" {
+		wrap = rebui.WrapWord
+	}
+	if w.Width <= 0 {
+		wrap = rebui.WrapNone
+	}
+	w.lines = w.lines[:0]
+	for _, b := range blocks.FromText(w.text, blocks.Config{Face: w.face, Width: w.Width, Wrap: wrap}) {
+		if tb, ok := b.(blocks.Text); ok {
+			w.lines = append(w.lines, tb)
+		}
+	}
+	w.clampScroll()
+}
+
+func (w *TextArea) lineHeight() float64 {
+	if w.face == nil {
+		return 0
+	}
+	return w.face.Metrics().HAscent + w.face.Metrics().HDescent
+}
+
+// lineAt returns the line containing the byte offset. An offset at the end of a line that is also the start of the next belongs to the next.
+func (w *TextArea) lineAt(i int) int {
+	for l := len(w.lines) - 1; l > 0; l-- {
+		if w.lines[l].Offset <= i {
+			return l
+		}
+	}
+	return 0
+}
+
+// lineEnd returns the last byte offset the cursor can be placed at on the line. If the line was wrapped without dropping a space, its end is the start of the next line, so the cursor stops before its last grapheme cluster instead.
+func (w *TextArea) lineEnd(l int) int {
+	end := w.lines[l].Offset + len(w.lines[l].Text)
+	if l+1 < len(w.lines) && w.lines[l+1].Offset == end && end > w.lines[l].Offset {
+		return prevBoundary(w.boundaries, end)
+	}
+	return end
+}
+
+// measure returns the x position of the byte offset within the line.
+func (w *TextArea) measure(l, i int) float64 {
+	if w.face == nil {
+		return 0
+	}
+	line := w.lines[l]
+	i = min(max(i, line.Offset), line.Offset+len(line.Text))
+	width, _ := text.Measure(w.text[line.Offset:i], w.face, 0)
+	return width
+}
+
+// indexAt returns the grapheme cluster boundary within the line nearest to the x position.
+func (w *TextArea) indexAt(l int, x float64) int {
+	start, end := w.lines[l].Offset, w.lineEnd(l)
+	prev, prevWidth := start, 0.0
+	for _, b := range w.boundaries {
+		if b <= start {
+			continue
+		}
+		if b > end {
+			break
+		}
+		width := w.measure(l, b)
+		if x > width {
+			prev, prevWidth = b, width
+			continue
+		}
+		if x-prevWidth < width-x {
+			return prev
+		}
+		return b
+	}
+	return prev
+}
+
+// getTextIndex returns the grapheme cluster boundary nearest to the given position.
+func (w *TextArea) getTextIndex(x, y float64) int {
+	l := 0
+	if lh := w.lineHeight(); lh > 0 {
+		l = min(max(int(math.Floor((y+w.ScrollY)/lh)), 0), len(w.lines)-1)
+	}
+	return w.indexAt(l, x)
+}
+
+// clampScroll keeps the scroll within the lines.
+func (w *TextArea) clampScroll() {
+	maxScroll := float64(len(w.lines))*w.lineHeight() - w.Height
+	w.ScrollY = max(min(w.ScrollY, maxScroll), 0)
+}
+
+// ScrollBy scrolls by the given amount, returning if the scroll changed.
+func (w *TextArea) ScrollBy(dy float64) bool {
+	y := w.ScrollY
+	w.ScrollY += dy
+	w.clampScroll()
+	return w.ScrollY != y
+}
+
+// refreshCursor scrolls to show the cursor's line and restarts its blinking.
+func (w *TextArea) refreshCursor() {
+	lh := w.lineHeight()
+	top := float64(w.lineAt(w.cursor)) * lh
+	if top < w.ScrollY {
+		w.ScrollY = top
+	} else if top+lh > w.ScrollY+w.Height {
+		w.ScrollY = top + lh - w.Height
+	}
+	w.clampScroll()
+
+	w.cursorHidden = false
+	w.lastTime = time.Now()
+}
+
+// moveCursor moves the cursor to the byte offset. If extend is set, the selection is extended from the end that the cursor is not at, or from the cursor if there is no selection. Otherwise the selection is cleared. Unless the move is vertical, the cursor's new x position is the one later vertical moves keep to.
+func (w *TextArea) moveCursor(i int, vertical, extend bool) {
+	if extend {
+		anchor := w.cursor
+		if w.selectStart != w.selectEnd {
+			anchor = w.selectStart + w.selectEnd - w.cursor
+		}
+		w.setSelect(min(anchor, i), max(anchor, i))
+	} else {
+		w.setSelect(0, 0)
+	}
+	w.cursor = i
+	if !vertical {
+		w.cursorX = w.measure(w.lineAt(i), i)
+	}
+	w.refreshCursor()
+}
+
+// moveLines moves the cursor up or down by the given number of lines, or to the start or end of the text if there are not enough lines. If extend is set, the selection is extended as with moveCursor.
+func (w *TextArea) moveLines(n int, extend bool) {
+	l := w.lineAt(w.cursor) + n
+	if l < 0 {
+		w.moveCursor(0, false, extend)
+	} else if l >= len(w.lines) {
+		w.moveCursor(len(w.text), false, extend)
+	} else {
+		w.moveCursor(w.indexAt(l, w.cursorX), true, extend)
+	}
+}
+
+func (w *TextArea) selectAll() {
+	w.selectInitial = 0
+	w.cursor = len(w.text)
+	w.cursorX = w.measure(w.lineAt(w.cursor), w.cursor)
+	w.setSelect(0, len(w.text))
+	w.refreshCursor()
+}
+
+func (w *TextArea) setSelect(x1, x2 int) {
+	w.selectStart = x1
+	w.selectEnd = x2
+}
+
+// replace replaces the text from start to end with s, leaving the cursor after s. The edit is recorded in the history as the given kind.
+func (w *TextArea) replace(start, end int, s string, kind editKind) {
+	w.history.record(w.state(), kind, w.HistoryDepth)
+	s = normalizeText(s)
+	w.cursor = start + len(s)
+	w.setText(w.text[:start] + s + w.text[end:])
+	w.cursorX = w.measure(w.lineAt(w.cursor), w.cursor)
+	w.refreshCursor()
+	w.history.edited(w.state())
+}
+
+// insert replaces the selection with s, or inserts s at the cursor if there is no selection.
+func (w *TextArea) insert(s string, kind editKind) {
+	if w.selectStart != w.selectEnd {
+		w.replace(w.selectStart, w.selectEnd, s, kind)
+	} else {
+		w.replace(w.cursor, w.cursor, s, kind)
+	}
+}
+
+func (w *TextArea) state() textState {
+	return textState{text: w.text, cursor: w.cursor, selectStart: w.selectStart, selectEnd: w.selectEnd}
+}
+
+func (w *TextArea) restore(s textState) {
+	w.setText(s.text)
+	w.cursor = s.cursor
+	w.cursorX = w.measure(w.lineAt(w.cursor), w.cursor)
+	w.setSelect(s.selectStart, s.selectEnd)
+	w.refreshCursor()
+}
+
+// Undo reverts the last edit, returning if there was one to undo.
+func (w *TextArea) Undo() bool {
+	s, ok := w.history.undo(w.state())
+	if ok {
+		w.restore(s)
+	}
+	return ok
+}
+
+// Redo reapplies the last undone edit, returning if there was one to redo. Any new edit discards the edits that can be redone.
+func (w *TextArea) Redo() bool {
+	s, ok := w.history.redo(w.state())
+	if ok {
+		w.restore(s)
+	}
+	return ok
+}
+
+// ClearHistory discards all edits that can be undone or redone.
+func (w *TextArea) ClearHistory() {
+	w.history.clear()
+}
+
+func (w *TextArea) Draw(screen *ebiten.Image, sop *ebiten.DrawImageOptions) {
+	x := sop.GeoM.Element(0, 2)
+	y := sop.GeoM.Element(1, 2)
+
+	if w.backgroundColor != nil {
+		vector.DrawFilledRect(screen, float32(x), float32(y), float32(w.Width), float32(w.Height), w.backgroundColor, false)
+	}
+
+	if w.face != nil {
+		// Clip the lines to our bounds.
+		area := screen.SubImage(image.Rect(int(x), int(y), int(math.Ceil(x+w.Width)), int(math.Ceil(y+w.Height)))).(*ebiten.Image)
+		lh := w.lineHeight()
+		for l := max(int(w.ScrollY/lh), 0); l < len(w.lines); l++ {
+			ly := float64(l)*lh - w.ScrollY
+			if ly >= w.Height {
+				break
+			}
+			line := w.lines[l]
+			end := line.Offset + len(line.Text)
+
+			if w.selectStart < w.selectEnd && w.selectStart <= end && w.selectEnd > line.Offset {
+				startX := w.measure(l, w.selectStart)
+				endX := w.measure(l, w.selectEnd)
+				if w.selectEnd > end && l+1 < len(w.lines) && w.lines[l+1].Offset > end {
+					endX += lh / 4 // Show that the line break is selected.
+				}
+				vector.DrawFilledRect(area, float32(x+startX), float32(y+ly), float32(endX-startX), float32(lh), color.RGBA{R: 128, G: 128, B: 128, A: 128}, true)
+			}
+
+			txtOptions := &text.DrawOptions{}
+			txtOptions.GeoM.Concat(sop.GeoM)
+			txtOptions.GeoM.Translate(0, ly)
+			txtOptions.ColorScale.ScaleWithColor(w.foregroundColor)
+			text.Draw(area, line.Text, w.face, txtOptions)
+		}
+
+		if w.showCursor {
+			if time.Since(w.lastTime) > time.Millisecond*500 {
+				w.lastTime = time.Now()
+				w.cursorHidden = !w.cursorHidden
+			}
+			if !w.cursorHidden {
+				l := w.lineAt(w.cursor)
+				cursorX := x + w.measure(l, w.cursor)
+				cursorY := y + float64(l)*lh - w.ScrollY
+
+				vector.StrokeLine(area, float32(cursorX), float32(cursorY), float32(cursorX), float32(cursorY+lh), 1, w.foregroundColor, false)
+			}
+		}
+	}
+
+	w.drawBorder(screen, float32(x), float32(y), float32(w.Width), float32(w.Height))
+}
+
+func (w *TextArea) HandleFocus(evt rebui.EventFocus) {
+	w.showCursor = true
+	w.refreshCursor()
+}
+
+func (w *TextArea) HandleUnfocus(evt rebui.EventUnfocus) {
+	w.showCursor = false
+}
+
+// HandlePointerPress moves the cursor to the pressed position and starts selecting from it, capturing the pointer so that the selection can be dragged beyond the area.
+func (w *TextArea) HandlePointerPress(evt rebui.EventPointerPress) {
+	w.moveCursor(w.getTextIndex(evt.RelativeX, evt.RelativeY), false, false)
+	w.selectInitial = w.cursor
+	w.selecting = true
+	w.selectPointer = evt.ID()
+	evt.CapturePointer()
+}
+
+// HandlePointerMove extends the selection to the pointer, scrolling if it is beyond the area.
+func (w *TextArea) HandlePointerMove(evt rebui.EventPointerMove) {
+	if !w.selecting || evt.ID() != w.selectPointer {
+		return
+	}
+	w.cursor = w.getTextIndex(evt.RelativeX, evt.RelativeY)
+	w.cursorX = w.measure(w.lineAt(w.cursor), w.cursor)
+	if w.cursor < w.selectInitial {
+		w.setSelect(w.cursor, w.selectInitial)
+	} else {
+		w.setSelect(w.selectInitial, w.cursor)
+	}
+	w.refreshCursor()
+}
+
+// HandlePointerRelease stops selecting.
+func (w *TextArea) HandlePointerRelease(evt rebui.EventPointerRelease) {
+	if evt.ID() == w.selectPointer {
+		w.selecting = false
+	}
+}
+
+// HandleLostCapture stops selecting.
+func (w *TextArea) HandleLostCapture(evt rebui.EventLostCapture) {
+	if evt.PointerID == w.selectPointer {
+		w.selecting = false
+	}
+}
+
+// HandlePointerWheel scrolls by the wheel's movement. The event is canceled if the area scrolled, so that containing views only scroll once this one can scroll no further.
+func (w *TextArea) HandlePointerWheel(evt rebui.EventPointerWheel) {
+	if w.ScrollBy(-evt.DY * scrollWheelStep) {
+		evt.Cancel()
+	}
+}
+
+func (w *TextArea) HandleKeyInput(evt rebui.EventKeyInput) {
+	if evt.Ctrl && strings.ContainsRune("acvxyzZ", evt.Rune) {
+		return
+	}
+	w.insert(string(evt.Rune), editTyping)
+	if unicode.IsSpace(evt.Rune) {
+		w.history.split() // Undo typing a word at a time.
+	}
+}
+
+func (w *TextArea) HandleKeyPress(evt rebui.EventKeyPress) {
+	switch evt.Key {
+	case ebiten.KeyBackspace:
+		if w.selectStart != w.selectEnd {
+			w.insert("", editOther)
+		} else if w.cursor > 0 {
+			w.replace(prevBoundary(w.boundaries, w.cursor), w.cursor, "", editDeleting)
+		}
+	case ebiten.KeyDelete:
+		if w.selectStart != w.selectEnd {
+			w.insert("", editOther)
+		} else if w.cursor < len(w.text) {
+			w.replace(w.cursor, nextBoundary(w.boundaries, w.cursor), "", editDeleting)
+		}
+	case ebiten.KeyEnter, ebiten.KeyNumpadEnter:
+		if evt.Ctrl {
+			if w.OnSubmit != nil {
+				w.OnSubmit(w.text)
+			}
+		} else {
+			w.insert("\n", editOther)
+		}
+		evt.PreventDefault() // Keep shortcuts bound to the same chord from also occurring.
+	case ebiten.KeyLeft:
+		w.moveCursor(prevBoundary(w.boundaries, w.cursor), false, evt.Shift)
+		evt.PreventDefault() // Keep the cursor keys from moving focus.
+	case ebiten.KeyRight:
+		w.moveCursor(nextBoundary(w.boundaries, w.cursor), false, evt.Shift)
+		evt.PreventDefault()
+	case ebiten.KeyUp:
+		w.moveLines(-1, evt.Shift)
+		evt.PreventDefault()
+	case ebiten.KeyDown:
+		w.moveLines(1, evt.Shift)
+		evt.PreventDefault()
+	case ebiten.KeyHome:
+		if evt.Ctrl {
+			w.moveCursor(0, false, evt.Shift)
+		} else {
+			w.moveCursor(w.lines[w.lineAt(w.cursor)].Offset, false, evt.Shift)
+		}
+		evt.PreventDefault()
+	case ebiten.KeyEnd:
+		if evt.Ctrl {
+			w.moveCursor(len(w.text), false, evt.Shift)
+		} else {
+			w.moveCursor(w.lineEnd(w.lineAt(w.cursor)), false, evt.Shift)
+		}
+		evt.PreventDefault()
+	case ebiten.KeyPageUp:
+		if lh := w.lineHeight(); lh > 0 {
+			w.moveLines(-max(int(w.Height/lh), 1), evt.Shift)
+		}
+		evt.PreventDefault()
+	case ebiten.KeyPageDown:
+		if lh := w.lineHeight(); lh > 0 {
+			w.moveLines(max(int(w.Height/lh), 1), evt.Shift)
+		}
+		evt.PreventDefault()
+	case ebiten.KeyC:
+		if evt.Ctrl {
+			if w.selectStart != w.selectEnd {
+				clipboard.SetText(w.text[w.selectStart:w.selectEnd])
+			}
+			evt.PreventDefault()
+		}
+	case ebiten.KeyX:
+		if evt.Ctrl {
+			if w.selectStart != w.selectEnd {
+				clipboard.SetText(w.text[w.selectStart:w.selectEnd])
+				w.insert("", editOther)
+			}
+			evt.PreventDefault()
+		}
+	case ebiten.KeyV:
+		if evt.Ctrl {
+			w.insert(clipboard.GetText(), editOther)
+			evt.PreventDefault()
+		}
+	case ebiten.KeyA:
+		if evt.Ctrl {
+			w.selectAll()
+			evt.PreventDefault()
+		}
+	case ebiten.KeyZ:
+		if evt.Ctrl {
+			if evt.Shift {
+				w.Redo()
+			} else {
+				w.Undo()
+			}
+			evt.PreventDefault()
+		}
+	case ebiten.KeyY:
+		if evt.Ctrl {
+			w.Redo()
+			evt.PreventDefault()
+		}
+	}
+}
+
+func init() {
+	rebui.RegisterWidget("TextArea", &TextArea{})
+}
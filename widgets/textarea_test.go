@@ -0,0 +1,70 @@
+package widgets
+
+import (
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/kettek/rebui"
+	"github.com/kettek/rebui/events"
+)
+
+func newTextArea(text string) *TextArea {
+	w := &TextArea{}
+	w.AssignFontFace(rebui.CurrentTheme().FontFace)
+	w.AssignText(text)
+	return w
+}
+
+func TestTextAreaUndo(t *testing.T) {
+	w := newTextArea("")
+	for _, r := range "one two" {
+		w.HandleKeyInput(&events.KeyInput{Rune: r})
+	}
+	w.HandleKeyPress(&events.KeyPress{Key: ebiten.KeyEnter})
+	for _, r := range "three" {
+		w.HandleKeyInput(&events.KeyInput{Rune: r})
+	}
+
+	steps := []struct {
+		action   func() bool
+		ok       bool
+		expected string
+	}{
+		{w.Undo, true, "one two\n"},
+		{w.Undo, true, "one two"},
+		{w.Undo, true, "one "},
+		{w.Undo, true, ""},
+		{w.Undo, false, ""},
+		{w.Redo, true, "one "},
+		{w.Redo, true, "one two"},
+	}
+	for i, s := range steps {
+		if ok := s.action(); ok != s.ok || w.text != s.expected {
+			t.Errorf("step %d: expected %v with %q, got %v with %q", i, s.ok, s.expected, ok, w.text)
+		}
+	}
+	if w.cursor != len(w.text) || w.selectStart != w.selectEnd {
+		t.Errorf("expected the cursor at the end without a selection, got %d and %d to %d", w.cursor, w.selectStart, w.selectEnd)
+	}
+
+	// Ctrl+Y redoes, and an edit after undoing discards what could be redone.
+	w.HandleKeyPress(&events.KeyPress{Key: ebiten.KeyY, Modifiers: events.Modifiers{Ctrl: true}})
+	if w.text != "one two\n" {
+		t.Errorf("expected Ctrl+Y to redo, got %q", w.text)
+	}
+	w.HandleKeyPress(&events.KeyPress{Key: ebiten.KeyZ, Modifiers: events.Modifiers{Ctrl: true}})
+	w.HandleKeyInput(&events.KeyInput{Rune: '!'})
+	if w.text != "one two!" || w.Redo() {
+		t.Errorf("expected an edit to discard the redos, got %q", w.text)
+	}
+
+	w.ClearHistory()
+	if w.Undo() || w.text != "one two!" {
+		t.Errorf("expected nothing to undo after clearing the history, got %q", w.text)
+	}
+	w.HandleKeyInput(&events.KeyInput{Rune: '?'})
+	w.AssignText("replaced")
+	if w.Undo() || w.text != "replaced" {
+		t.Errorf("expected assigning the text to discard the history, got %q", w.text)
+	}
+}
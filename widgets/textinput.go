@@ -4,6 +4,7 @@ import (
 	"image/color"
 	"strings"
 	"time"
+	"unicode"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/text/v2"
@@ -16,6 +17,7 @@ type TextInput struct {
 	Label
 	Border
 	text            string
+	boundaries      []int // The byte offsets of the grapheme clusters in text, which the cursor and selection are kept on.
 	canvas          *ebiten.Image
 	cursor          int
 	showCursor      bool
@@ -26,19 +28,21 @@ type TextInput struct {
 	selectStart     int
 	selectEnd       int
 	ScrollX         float64
+	align           rebui.Alignment // The horizontal alignment, which is applied here rather than by the Label so that the text can scroll.
 	backgroundColor color.Color
 	OnChange        func(string)
 	OnSubmit        func(string)
 	lastTime        time.Time
 	cursorHidden    bool
-	controlHeld     bool // TODO: Move this to be as part of KeyEvent system.
 	obfuscated      bool
+	history         textHistory
+	HistoryDepth    int // HistoryDepth is the most edits that can be undone. If 0, 100 is used.
 }
 
 func (w *TextInput) AssignWidth(width float64) {
 	w.Width = width
 	w.refreshCanvas()
-	w.refreshText()
+	w.refreshScroll()
 }
 
 func (w *TextInput) AssignHeight(height float64) {
@@ -47,27 +51,36 @@ func (w *TextInput) AssignHeight(height float64) {
 	w.refreshText()
 }
 
+// AssignText replaces the text, discarding the edit history.
 func (w *TextInput) AssignText(text string) {
+	w.setText(text)
+	w.history.clear()
+}
+
+// setText replaces the text, clearing the selection and keeping the cursor within it.
+func (w *TextInput) setText(text string) {
 	w.selectStart = 0
 	w.selectEnd = 0
 	w.text = text
+	w.boundaries = graphemeBoundaries(text)
 	if w.obfuscated {
-		w.Label.AssignText(strings.Repeat("*", len(text)))
+		w.Label.AssignText(strings.Repeat("*", len(w.boundaries)-1))
 	} else {
 		w.Label.AssignText(text)
 	}
 	if w.cursor > len(text) {
 		w.cursor = len(text)
 	}
+	w.cursor = snapBoundary(w.boundaries, w.cursor)
 	if w.OnChange != nil {
 		w.OnChange(text)
 	}
-	w.refreshText()
+	w.refreshScroll()
 }
 
 func (w *TextInput) AssignFontSize(size float64) {
 	w.Label.AssignFontSize(size)
-	w.refreshText()
+	w.refreshScroll()
 }
 
 func (w *TextInput) AssignForegroundColor(clr color.Color) {
@@ -79,15 +92,25 @@ func (w *TextInput) AssignBackgroundColor(clr color.Color) {
 	w.backgroundColor = clr
 }
 
+func (w *TextInput) AssignHorizontalAlignment(align rebui.Alignment) {
+	w.align = align
+	w.refreshScroll()
+}
+
 func (w *TextInput) AssignObfuscation(b bool) {
 	w.obfuscated = b
-	w.AssignText(w.text)
+	w.setText(w.text)
 }
 
 func (w *TextInput) GetObfuscation() bool {
 	return w.obfuscated
 }
 
+// GetCursor returns the text cursor, shown while the input is hovered.
+func (w *TextInput) GetCursor() rebui.Cursor {
+	return rebui.CursorText
+}
+
 func (w *TextInput) refreshCanvas() {
 	if w.Width == 0 || w.Height == 0 {
 		return
@@ -103,25 +126,65 @@ func (w *TextInput) refreshText() {
 		return
 	}
 	sop := &ebiten.DrawImageOptions{}
-	sop.GeoM.Translate(-w.ScrollX, 0)
+	sop.GeoM.Translate(w.textX()-w.ScrollX, 0)
 	w.canvas.Clear()
 	w.Label.Draw(w.canvas, sop)
 }
 
-func (w *TextInput) refreshCursor() {
-	w.cursorHeight = w.face.Metrics().HAscent + w.face.Metrics().HDescent
-	if w.cursor == len(w.text) {
-		w.cursorX, _ = text.Measure(w.text, w.face, 0)
+// measure returns the width of the displayed text before the given byte offset of the text.
+func (w *TextInput) measure(i int) float64 {
+	if w.face == nil {
+		return 0
+	}
+	var s string
+	if w.obfuscated {
+		s = strings.Repeat("*", boundaryIndex(w.boundaries, i))
 	} else {
-		w.cursorX, _ = text.Measure(w.text[:w.cursor], w.face, 0)
+		s = w.text[:i]
 	}
-	// TODO: Implement halign logic for cursor.
-	/*switch w.halign {
+	width, _ := text.Measure(s, w.face, 0)
+	return width
+}
+
+// textX returns the x position of the start of the text before scrolling. Text narrower than the input is aligned within it, while wider text starts at the left so that it can be scrolled through.
+func (w *TextInput) textX() float64 {
+	width := w.measure(len(w.text))
+	switch w.align {
 	case rebui.AlignCenter:
-		w.cursorX -= w.Width / 2
+		return max((w.Width-width)/2, 0)
 	case rebui.AlignRight:
-		w.cursorX = w.Width - w.cursorX
-	}*/
+		return max(w.Width-width, 0)
+	}
+	return 0
+}
+
+// refreshScroll repositions the cursor and the scroll after the text, its size, or its alignment changes.
+func (w *TextInput) refreshScroll() {
+	w.cursorX = w.textX() + w.measure(w.cursor)
+	w.scrollToCursor()
+	w.refreshText()
+}
+
+// scrollToCursor scrolls as little as needed to show the cursor, while keeping the scroll within the text.
+func (w *TextInput) scrollToCursor() {
+	scrollX := w.ScrollX
+	if w.cursorX < scrollX {
+		scrollX = w.cursorX
+	} else if w.cursorX > scrollX+w.Width-1 {
+		scrollX = w.cursorX - w.Width + 1 // Leave room for the cursor itself.
+	}
+	maxScroll := w.textX() + w.measure(len(w.text)) - w.Width + 1
+	scrollX = max(min(scrollX, maxScroll), 0)
+	if scrollX != w.ScrollX {
+		w.ScrollX = scrollX
+		w.refreshText()
+	}
+}
+
+func (w *TextInput) refreshCursor() {
+	w.cursorHeight = w.face.Metrics().HAscent + w.face.Metrics().HDescent
+	w.cursorX = w.textX() + w.measure(w.cursor)
+	w.scrollToCursor()
 	switch w.valign {
 	case rebui.AlignMiddle:
 		w.cursorY = w.Height/2 - w.cursorHeight/2
@@ -146,8 +209,9 @@ func (w *TextInput) Draw(screen *ebiten.Image, sop *ebiten.DrawImageOptions) {
 	screen.DrawImage(w.canvas, sop)
 
 	if w.selectStart != w.selectEnd {
-		startX, _ := text.Measure(w.text[:w.selectStart], w.face, 0)
-		endX, _ := text.Measure(w.text[:w.selectEnd], w.face, 0)
+		// Keep the selection within the input when it is scrolled out of view.
+		startX := min(max(w.textX()+w.measure(w.selectStart)-w.ScrollX, 0), w.Width)
+		endX := min(max(w.textX()+w.measure(w.selectEnd)-w.ScrollX, 0), w.Width)
 		vector.DrawFilledRect(screen, float32(x+startX), float32(y+w.cursorY)-1, float32(endX-startX), float32(w.cursorHeight)+2, color.RGBA{R: 128, G: 128, B: 128, A: 128}, true)
 	}
 
@@ -177,23 +241,79 @@ func (w *TextInput) HandleUnfocus(evt rebui.EventUnfocus) {
 }
 
 func (w *TextInput) HandlePointerPress(evt rebui.EventPointerPress) {
+	if evt.Shift {
+		w.moveCursor(w.getTextIndex(evt.RelativeX), true)
+		w.selectInitial = w.selectStart + w.selectEnd - w.cursor // The end of the selection that stays put.
+		return
+	}
 	w.cursor = w.getTextIndex(evt.RelativeX)
 	w.refreshCursor()
 	w.selectInitial = w.cursor
 	w.setSelect(w.cursor, w.cursor)
 }
 
+// HandlePointerTap selects the word tapped upon for a double-click and all of the text for a triple-click.
+func (w *TextInput) HandlePointerTap(evt rebui.EventPointerTap) {
+	switch {
+	case evt.Count == 2:
+		start, end := wordAt(w.wordSpans(), w.getTextIndex(evt.RelativeX), len(w.text))
+		w.selectInitial = start
+		w.cursor = end
+		w.setSelect(start, end)
+		w.refreshCursor()
+	case evt.Count >= 3:
+		w.selectAll()
+	}
+}
+
+// wordSpans returns the spans of the words in the text. Obfuscated text is treated as a single word, so as not to reveal where its words are.
+func (w *TextInput) wordSpans() [][2]int {
+	if w.obfuscated {
+		return [][2]int{{0, len(w.text)}}
+	}
+	return wordSpans(w.text)
+}
+
+// moveCursor moves the cursor to i. If extend is set, the selection is extended from the end that the cursor is not at, or from the cursor if there is no selection. Otherwise the selection is cleared.
+func (w *TextInput) moveCursor(i int, extend bool) {
+	if extend {
+		anchor := w.cursor
+		if w.selectStart != w.selectEnd {
+			anchor = w.selectStart + w.selectEnd - w.cursor
+		}
+		w.setSelect(min(anchor, i), max(anchor, i))
+	} else {
+		w.setSelect(0, 0)
+	}
+	w.cursor = i
+	w.refreshCursor()
+}
+
+func (w *TextInput) selectAll() {
+	w.selectInitial = 0
+	w.cursor = len(w.text)
+	w.setSelect(0, len(w.text))
+	w.refreshCursor()
+}
+
+// getTextIndex returns the grapheme cluster boundary nearest to the given x position.
 func (w *TextInput) getTextIndex(x float64) int {
 	if len(w.text) == 0 {
 		return 0
 	}
+	x += w.ScrollX - w.textX() // Find the x position within the text.
 	// This seems awful, but I can't think of a more reliable way to fetch such information.
-	for i := range w.text {
-		width, _ := text.Measure(w.text[:i], w.face, 0)
+	prev, prevWidth := 0, 0.0
+	for _, b := range w.boundaries {
+		width := w.measure(b)
 		if x > width {
+			prev, prevWidth = b, width
 			continue
 		}
-		return i
+		if x-prevWidth < width-x {
+			return prev
+		}
+		return b
 	}
 	return len(w.text)
 }
@@ -204,117 +324,141 @@ func (w *TextInput) setSelect(x1, x2 int) {
 }
 
 func (w *TextInput) HandlePointerGlobalMove(evt rebui.EventPointerMove) {
-	index := w.getTextIndex(evt.RelativeX)
-	if index < w.selectInitial {
-		w.setSelect(index, w.selectInitial)
+	w.cursor = w.getTextIndex(evt.RelativeX)
+	w.setSelect(min(w.cursor, w.selectInitial), max(w.cursor, w.selectInitial))
+	w.refreshCursor()
+}
+
+// replace replaces the text from start to end with s, leaving the cursor after s. The edit is recorded in the history as the given kind.
+func (w *TextInput) replace(start, end int, s string, kind editKind) {
+	w.history.record(w.state(), kind, w.HistoryDepth)
+	w.cursor = start + len(s)
+	w.setText(w.text[:start] + s + w.text[end:])
+	w.refreshCursor()
+	w.history.edited(w.state())
+}
+
+// insert replaces the selection with s, or inserts s at the cursor if there is no selection.
+func (w *TextInput) insert(s string, kind editKind) {
+	if w.selectStart != w.selectEnd {
+		w.replace(w.selectStart, w.selectEnd, s, kind)
 	} else {
-		w.setSelect(w.selectInitial, index)
+		w.replace(w.cursor, w.cursor, s, kind)
+	}
+}
+
+func (w *TextInput) state() textState {
+	return textState{text: w.text, cursor: w.cursor, selectStart: w.selectStart, selectEnd: w.selectEnd}
+}
+
+func (w *TextInput) restore(s textState) {
+	w.setText(s.text)
+	w.cursor = s.cursor
+	w.setSelect(s.selectStart, s.selectEnd)
+	w.refreshCursor()
+}
+
+// Undo reverts the last edit, returning if there was one to undo.
+func (w *TextInput) Undo() bool {
+	s, ok := w.history.undo(w.state())
+	if ok {
+		w.restore(s)
+	}
+	return ok
+}
+
+// Redo reapplies the last undone edit, returning if there was one to redo. Any new edit discards the edits that can be redone.
+func (w *TextInput) Redo() bool {
+	s, ok := w.history.redo(w.state())
+	if ok {
+		w.restore(s)
 	}
+	return ok
+}
+
+// ClearHistory discards all edits that can be undone or redone.
+func (w *TextInput) ClearHistory() {
+	w.history.clear()
 }
 
 func (w *TextInput) HandleKeyInput(evt rebui.EventKeyInput) {
-	if w.controlHeld && (evt.Rune == 'v' || evt.Rune == 'c' || evt.Rune == 'a') {
+	if evt.Ctrl && strings.ContainsRune("acvxyzZ", evt.Rune) {
 		return
 	}
-	if w.selectStart != w.selectEnd {
-		var text string
-		if w.selectStart == 0 {
-			text = w.text[w.selectEnd:]
-		} else {
-			text = w.text[:w.selectStart] + string(evt.Rune) + w.text[w.selectEnd:]
-		}
-		w.cursor = w.selectStart
-		w.AssignText(text)
-	} else {
-		w.AssignText(w.text[:w.cursor] + string(evt.Rune) + w.text[w.cursor:])
+	w.insert(string(evt.Rune), editTyping)
+	if unicode.IsSpace(evt.Rune) {
+		w.history.split() // Undo typing a word at a time.
 	}
-	w.cursor++
-	w.refreshCursor()
 }
 
 func (w *TextInput) HandleKeyPress(evt rebui.EventKeyPress) {
 	if evt.Key == ebiten.KeyBackspace {
-		if len(w.text) > 0 {
-			if w.selectStart != w.selectEnd {
-				var text string
-				if w.selectStart == 0 {
-					text = w.text[w.selectEnd:]
-				} else {
-					text = w.text[:w.selectStart] + w.text[w.selectEnd:]
-				}
-				w.cursor = w.selectStart
-				w.AssignText(text)
-				w.refreshCursor()
-			} else if w.cursor > 0 {
-				var text string
-				if w.cursor == len(w.text) {
-					text = w.text[:len(w.text)-1]
-				} else {
-					text = w.text[:w.cursor-1] + w.text[w.cursor:]
-				}
-				w.cursor--
-				w.AssignText(text)
-				w.refreshCursor()
-			}
+		if w.selectStart != w.selectEnd {
+			w.insert("", editOther)
+		} else if evt.Ctrl && w.cursor > 0 {
+			w.replace(prevWordStart(w.wordSpans(), w.cursor), w.cursor, "", editOther)
+		} else if w.cursor > 0 {
+			w.replace(prevBoundary(w.boundaries, w.cursor), w.cursor, "", editDeleting)
 		}
 	} else if evt.Key == ebiten.KeyDelete {
-		if len(w.text) > 0 {
-			if w.selectStart != w.selectEnd {
-				var text string
-				if w.selectStart == 0 {
-					text = w.text[w.selectEnd:]
-				} else {
-					text = w.text[:w.selectStart] + w.text[w.selectEnd:]
-				}
-				w.cursor = w.selectStart
-				w.AssignText(text)
-				w.refreshCursor()
-			} else if w.cursor < len(w.text) {
-				var text string
-				if w.cursor == len(w.text)-1 {
-					text = w.text[:len(w.text)-1]
-				} else {
-					text = w.text[:w.cursor] + w.text[w.cursor+1:]
-				}
-				w.AssignText(text)
-			}
+		if w.selectStart != w.selectEnd {
+			w.insert("", editOther)
+		} else if evt.Ctrl && w.cursor < len(w.text) {
+			w.replace(w.cursor, nextWordEnd(w.wordSpans(), w.cursor, len(w.text)), "", editOther)
+		} else if w.cursor < len(w.text) {
+			w.replace(w.cursor, nextBoundary(w.boundaries, w.cursor), "", editDeleting)
 		}
 	} else if evt.Key == ebiten.KeyLeft {
-		if w.cursor > 0 {
-			w.cursor--
+		if evt.Ctrl {
+			w.moveCursor(prevWordStart(w.wordSpans(), w.cursor), evt.Shift)
+		} else if w.selectStart != w.selectEnd && !evt.Shift {
+			w.moveCursor(w.selectStart, false) // Collapse the selection to its start.
+		} else {
+			w.moveCursor(prevBoundary(w.boundaries, w.cursor), evt.Shift)
 		}
-		w.setSelect(0, 0)
-		w.refreshCursor()
+		evt.PreventDefault() // Keep the cursor keys from moving focus.
 	} else if evt.Key == ebiten.KeyRight {
-		if w.cursor < len(w.text) {
-			w.cursor++
+		if evt.Ctrl {
+			w.moveCursor(nextWordEnd(w.wordSpans(), w.cursor, len(w.text)), evt.Shift)
+		} else if w.selectStart != w.selectEnd && !evt.Shift {
+			w.moveCursor(w.selectEnd, false) // Collapse the selection to its end.
+		} else {
+			w.moveCursor(nextBoundary(w.boundaries, w.cursor), evt.Shift)
 		}
-		w.setSelect(0, 0)
-		w.refreshCursor()
+		evt.PreventDefault()
+	} else if evt.Key == ebiten.KeyHome {
+		w.moveCursor(0, evt.Shift)
+		evt.PreventDefault()
+	} else if evt.Key == ebiten.KeyEnd {
+		w.moveCursor(len(w.text), evt.Shift)
+		evt.PreventDefault()
 	} else if evt.Key == ebiten.KeyEnter {
 		if w.OnSubmit != nil {
 			w.OnSubmit(w.text)
 		}
-	} else if evt.Key == ebiten.KeyControl {
-		w.controlHeld = true
-	} else if evt.Key == ebiten.KeyC && w.controlHeld {
+	} else if evt.Key == ebiten.KeyC && evt.Ctrl {
 		if w.selectStart != w.selectEnd {
 			clipboard.SetText(w.text[w.selectStart:w.selectEnd])
 		}
-	} else if evt.Key == ebiten.KeyV && w.controlHeld {
-		text := w.text[:w.cursor] + clipboard.GetText() + w.text[w.cursor:]
-		w.cursor += len(clipboard.GetText())
-		w.AssignText(text)
-		w.refreshCursor()
-	} else if evt.Key == ebiten.KeyA && w.controlHeld {
-		w.setSelect(0, len(w.text))
-		w.refreshCursor()
-	}
-}
-
-func (w *TextInput) HandleKeyRelease(evt rebui.EventKeyRelease) {
-	if evt.Key == ebiten.KeyControl {
-		w.controlHeld = false
+		evt.PreventDefault() // Keep shortcuts bound to the same chord from also occurring.
+	} else if evt.Key == ebiten.KeyX && evt.Ctrl {
+		if w.selectStart != w.selectEnd {
+			clipboard.SetText(w.text[w.selectStart:w.selectEnd])
+			w.insert("", editOther)
+		}
+		evt.PreventDefault()
+	} else if evt.Key == ebiten.KeyV && evt.Ctrl {
+		w.insert(clipboard.GetText(), editOther)
+		evt.PreventDefault()
+	} else if evt.Key == ebiten.KeyA && evt.Ctrl {
+		w.selectAll()
+		evt.PreventDefault()
+	} else if (evt.Key == ebiten.KeyZ && evt.Ctrl && evt.Shift) || (evt.Key == ebiten.KeyY && evt.Ctrl) {
+		w.Redo()
+		evt.PreventDefault()
+	} else if evt.Key == ebiten.KeyZ && evt.Ctrl {
+		w.Undo()
+		evt.PreventDefault()
 	}
 }
 
@@ -0,0 +1,229 @@
+package widgets
+
+import (
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/kettek/rebui"
+	_ "github.com/kettek/rebui/defaults/font"
+	"github.com/kettek/rebui/events"
+)
+
+const (
+	composedE = "e\u0301"                                    // "é" as an "e" and a combining acute accent.
+	family    = "\U0001F468\u200d\U0001F469\u200d\U0001F467" // A family emoji joined with zero width joiners.
+)
+
+func newTextInput(text string) *TextInput {
+	w := &TextInput{}
+	w.AssignFontFace(rebui.CurrentTheme().FontFace)
+	w.AssignText(text)
+	return w
+}
+
+func typeText(w *TextInput, s string) {
+	for _, r := range s {
+		w.HandleKeyInput(&events.KeyInput{Rune: r})
+	}
+}
+
+func pressKey(w *TextInput, k ebiten.Key, m events.Modifiers) {
+	w.HandleKeyPress(&events.KeyPress{Key: k, Modifiers: m})
+}
+
+// checkCursor fails the test if the cursor or selection is not on a grapheme cluster boundary.
+func checkCursor(t *testing.T, w *TextInput) {
+	t.Helper()
+	b := graphemeBoundaries(w.text)
+	for _, i := range []int{w.cursor, w.selectStart, w.selectEnd} {
+		if snapBoundary(b, i) != i {
+			t.Errorf("%d is within a grapheme cluster of %q", i, w.text)
+		}
+	}
+}
+
+func TestTextInputInsert(t *testing.T) {
+	w := newTextInput("")
+	typeText(w, "caf"+composedE)
+	if w.text != "caf"+composedE || w.cursor != len(w.text) {
+		t.Errorf("expected %q with the cursor at its end, got %q at %d", "caf"+composedE, w.text, w.cursor)
+	}
+	checkCursor(t, w)
+
+	pressKey(w, ebiten.KeyHome, events.Modifiers{})
+	typeText(w, "日本")
+	if w.text != "日本caf"+composedE || w.cursor != len("日本") {
+		t.Errorf("expected %q with the cursor after 日本, got %q at %d", "日本caf"+composedE, w.text, w.cursor)
+	}
+
+	pressKey(w, ebiten.KeyEnd, events.Modifiers{})
+	typeText(w, family)
+	if w.text != "日本caf"+composedE+family || w.cursor != len(w.text) {
+		t.Errorf("expected the emoji to be appended, got %q at %d", w.text, w.cursor)
+	}
+	checkCursor(t, w)
+}
+
+func TestTextInputBackspace(t *testing.T) {
+	tests := []struct {
+		text, expected string
+	}{
+		{"caf\u00e9", "caf"},
+		{"caf" + composedE, "caf"},
+		{"a\u0323\u0301", ""},
+		{"日本語", "日本"},
+		{"\u304b\u3099", ""},
+		{"a" + family, "a"},
+		{family + family, family},
+		{"\U0001F1EF\U0001F1F5", ""},
+	}
+	for _, tt := range tests {
+		w := newTextInput(tt.text)
+		pressKey(w, ebiten.KeyEnd, events.Modifiers{})
+		pressKey(w, ebiten.KeyBackspace, events.Modifiers{})
+		if w.text != tt.expected || w.cursor != len(tt.expected) {
+			t.Errorf("backspace in %q: expected %q with the cursor at %d, got %q at %d", tt.text, tt.expected, len(tt.expected), w.text, w.cursor)
+		}
+		checkCursor(t, w)
+	}
+}
+
+func TestTextInputDelete(t *testing.T) {
+	tests := []struct {
+		text, expected string
+	}{
+		{"\u00e9a", "a"},
+		{composedE + "a", "a"},
+		{"日本語", "本語"},
+		{family + "a", "a"},
+		{"\U0001F1EF\U0001F1F5\U0001F1FA\U0001F1F8", "\U0001F1FA\U0001F1F8"},
+	}
+	for _, tt := range tests {
+		w := newTextInput(tt.text)
+		pressKey(w, ebiten.KeyHome, events.Modifiers{})
+		pressKey(w, ebiten.KeyDelete, events.Modifiers{})
+		if w.text != tt.expected || w.cursor != 0 {
+			t.Errorf("delete in %q: expected %q with the cursor at 0, got %q at %d", tt.text, tt.expected, w.text, w.cursor)
+		}
+		checkCursor(t, w)
+	}
+}
+
+func TestTextInputCursorMovement(t *testing.T) {
+	s := composedE + "日" + family + "b"
+	stops := []int{0, 3, 6, 24, 25}
+
+	w := newTextInput(s)
+	pressKey(w, ebiten.KeyHome, events.Modifiers{})
+	for _, stop := range stops[1:] {
+		pressKey(w, ebiten.KeyRight, events.Modifiers{})
+		if w.cursor != stop {
+			t.Errorf("expected right to move the cursor to %d, got %d", stop, w.cursor)
+		}
+	}
+	pressKey(w, ebiten.KeyRight, events.Modifiers{})
+	if w.cursor != len(s) {
+		t.Errorf("expected the cursor to stay at the end, got %d", w.cursor)
+	}
+	for i := len(stops) - 2; i >= 0; i-- {
+		pressKey(w, ebiten.KeyLeft, events.Modifiers{})
+		if w.cursor != stops[i] {
+			t.Errorf("expected left to move the cursor to %d, got %d", stops[i], w.cursor)
+		}
+	}
+
+	// Selections are extended by whole clusters too.
+	pressKey(w, ebiten.KeyEnd, events.Modifiers{})
+	pressKey(w, ebiten.KeyLeft, events.Modifiers{Shift: true})
+	pressKey(w, ebiten.KeyLeft, events.Modifiers{Shift: true})
+	if w.selectStart != 6 || w.selectEnd != 25 || w.cursor != 6 {
+		t.Errorf("expected the emoji and b to be selected, got %d to %d with the cursor at %d", w.selectStart, w.selectEnd, w.cursor)
+	}
+	checkCursor(t, w)
+
+	// A cursor left within a cluster is moved to its end when the text is assigned.
+	w.setSelect(0, 0)
+	w.cursor = 1
+	w.AssignText(s)
+	if w.cursor != 3 {
+		t.Errorf("expected the cursor to be moved to the end of the first cluster, got %d", w.cursor)
+	}
+	checkCursor(t, w)
+}
+
+func TestTextInputObfuscated(t *testing.T) {
+	w := newTextInput("")
+	w.AssignObfuscation(true)
+	w.AssignText(composedE + "日" + family + "\U0001F1EF\U0001F1F5")
+	if w.Label.text != "****" {
+		t.Errorf("expected one * for each of the 4 clusters, got %q", w.Label.text)
+	}
+
+	pressKey(w, ebiten.KeyEnd, events.Modifiers{})
+	pressKey(w, ebiten.KeyBackspace, events.Modifiers{})
+	if w.Label.text != "***" {
+		t.Errorf("expected 3 *s after a backspace, got %q", w.Label.text)
+	}
+	typeText(w, "a\u0323\u0301語")
+	if w.Label.text != "*****" {
+		t.Errorf("expected 5 *s after typing 2 clusters, got %q", w.Label.text)
+	}
+	checkCursor(t, w)
+
+	w.AssignObfuscation(false)
+	if w.Label.text != w.text {
+		t.Errorf("expected the text to be shown once not obfuscated, got %q", w.Label.text)
+	}
+}
+
+func TestTextInputUndo(t *testing.T) {
+	w := newTextInput("")
+	typeText(w, "hello world")
+	pressKey(w, ebiten.KeyBackspace, events.Modifiers{})
+	pressKey(w, ebiten.KeyBackspace, events.Modifiers{})
+
+	steps := []struct {
+		action   func() bool
+		ok       bool
+		expected string
+	}{
+		{w.Undo, true, "hello world"},
+		{w.Undo, true, "hello "},
+		{w.Undo, true, ""},
+		{w.Undo, false, ""},
+		{w.Redo, true, "hello "},
+		{w.Redo, true, "hello world"},
+		{w.Redo, true, "hello wor"},
+		{w.Redo, false, "hello wor"},
+	}
+	for i, s := range steps {
+		if ok := s.action(); ok != s.ok || w.text != s.expected {
+			t.Errorf("step %d: expected %v with %q, got %v with %q", i, s.ok, s.expected, ok, w.text)
+		}
+		checkCursor(t, w)
+	}
+
+	// Ctrl+Z undoes, and an edit after undoing discards what could be redone.
+	pressKey(w, ebiten.KeyZ, events.Modifiers{Ctrl: true})
+	typeText(w, "!")
+	if w.text != "hello world!" {
+		t.Errorf("expected %q, got %q", "hello world!", w.text)
+	}
+	if w.Redo() {
+		t.Errorf("expected nothing to redo after an edit, got %q", w.text)
+	}
+	pressKey(w, ebiten.KeyZ, events.Modifiers{Ctrl: true, Shift: true})
+	if w.text != "hello world!" {
+		t.Errorf("expected Ctrl+Shift+Z to have nothing to redo, got %q", w.text)
+	}
+
+	w.ClearHistory()
+	if w.Undo() || w.text != "hello world!" {
+		t.Errorf("expected nothing to undo after clearing the history, got %q", w.text)
+	}
+	typeText(w, "?")
+	w.AssignText("replaced")
+	if w.Undo() || w.text != "replaced" {
+		t.Errorf("expected assigning the text to discard the history, got %q", w.text)
+	}
+}